@@ -3,6 +3,7 @@ package database
 import (
 	"github.com/quarkcloudio/quark-go/v3/dal/db"
 	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/queue"
 )
 
 // 执行数据库操作
@@ -15,6 +16,32 @@ func Handle() {
 		&model.Banner{},
 		&model.BannerCategory{},
 		&model.Navigation{},
+		&model.Item{},
+		&model.ItemAttr{},
+		&model.ItemAttrValue{},
+		&model.Order{},
+		&model.OrderDetail{},
+		&model.OrderLog{},
+		&model.OrderRefund{},
+		&model.PayReconciliation{},
+		&model.Bill{},
+		&model.UserBalance{},
+		&model.BillRecord{},
+		&model.Clerk{},
+		&model.Merchant{},
+		&model.Cart{},
+		&model.UserAddress{},
+		&model.Coupon{},
+		&model.UserCoupon{},
+		&model.Job{},
+		&model.JobRun{},
+		&model.SmsCode{},
+		&model.SmsCounter{},
+		&model.UserWechat{},
+		&model.UserSession{},
+		&model.UserDeletion{},
+		&queue.Job{},
+		&queue.FailedJob{},
 	)
 
 	// 数据填充
@@ -23,4 +50,12 @@ func Handle() {
 	(&model.Banner{}).Seeder()
 	(&model.BannerCategory{}).Seeder()
 	(&model.Navigation{}).Seeder()
+	(&model.Item{}).Seeder()
+	(&model.Order{}).Seeder()
+	(&model.PayReconciliation{}).Seeder()
+	(&model.Bill{}).Seeder()
+	(&model.Clerk{}).Seeder()
+	(&model.Merchant{}).Seeder()
+	(&model.Coupon{}).Seeder()
+	(&model.Job{}).Seeder()
 }
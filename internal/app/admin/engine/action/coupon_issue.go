@@ -0,0 +1,92 @@
+package action
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource/actions"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"gorm.io/gorm"
+)
+
+type CouponIssueAction struct {
+	actions.ModalForm
+}
+
+// 发放优惠券
+func CouponIssue() *CouponIssueAction {
+	return &CouponIssueAction{}
+}
+
+// 初始化
+func (p *CouponIssueAction) Init(ctx *quark.Context) interface{} {
+
+	// 文字
+	p.Name = "发放"
+
+	// 类型
+	p.Type = "link"
+
+	// 设置按钮大小,large | middle | small | default
+	p.Size = "small"
+
+	// 执行成功后刷新的组件
+	p.Reload = "table"
+
+	// 关闭时销毁 Modal 里的子元素
+	p.DestroyOnClose = true
+
+	// 设置展示位置
+	p.SetOnlyOnIndexTableRow(true)
+
+	// 行为接口接收的参数
+	p.SetApiParams([]string{
+		"id",
+	})
+
+	return p
+}
+
+// 字段
+func (p *CouponIssueAction) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Hidden("id", "ID"),
+
+		field.Number("uid", "用户ID").
+			SetHelp("发放不受公开领取和领取时间限制，但受发放总量和每人限领限制").
+			SetRules([]rule.Rule{
+				rule.Required("请填写用户ID"),
+			}),
+	}
+}
+
+// 表单数据（异步获取）
+func (p *CouponIssueAction) Data(ctx *quark.Context) map[string]interface{} {
+	return map[string]interface{}{
+		"id": ctx.Query("id"),
+	}
+}
+
+// 执行行为句柄
+func (p *CouponIssueAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	var param struct {
+		Uid int `json:"uid"`
+	}
+	if err := ctx.Bind(&param); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	coupon := model.Coupon{}
+	if err := query.First(&coupon).Error; err != nil {
+		return ctx.CJSONError("优惠券不存在")
+	}
+
+	if _, err := service.NewCouponService().Issue(coupon.Id, param.Uid); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	return ctx.CJSONOk("发放成功")
+}
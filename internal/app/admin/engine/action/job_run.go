@@ -0,0 +1,61 @@
+package action
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource/actions"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/scheduler"
+	"gorm.io/gorm"
+)
+
+type JobRunNowAction struct {
+	actions.Action
+}
+
+// 立即执行任务
+func JobRunNow() *JobRunNowAction {
+	return &JobRunNowAction{}
+}
+
+// 初始化
+func (p *JobRunNowAction) Init(ctx *quark.Context) interface{} {
+
+	// 文字
+	p.Name = "立即执行"
+
+	// 类型
+	p.Type = "link"
+
+	// 设置按钮大小,large | middle | small | default
+	p.Size = "small"
+
+	// 执行成功后刷新的组件
+	p.Reload = "table"
+
+	// 执行前确认
+	p.WithConfirm("确定要立即执行该任务吗？", "任务将在后台执行，执行结果请查看任务日志", "modal")
+
+	// 设置展示位置
+	p.SetOnlyOnIndexTableRow(true)
+
+	// 行为接口接收的参数
+	p.SetApiParams([]string{
+		"id",
+	})
+
+	return p
+}
+
+// 执行行为句柄
+func (p *JobRunNowAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	job := model.Job{}
+	if err := query.First(&job).Error; err != nil {
+		return ctx.CJSONError("任务不存在")
+	}
+
+	if err := scheduler.NewScheduler().RunNow(job.Name); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	return ctx.CJSONOk("任务已开始执行")
+}
@@ -0,0 +1,192 @@
+package action
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	appservice "github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource/actions"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"gorm.io/gorm"
+)
+
+type RefundApproveAction struct {
+	actions.ModalForm
+}
+
+type RefundRejectAction struct {
+	actions.ModalForm
+}
+
+// 同意退款
+func RefundApprove() *RefundApproveAction {
+	return &RefundApproveAction{}
+}
+
+// 拒绝退款
+func RefundReject() *RefundRejectAction {
+	return &RefundRejectAction{}
+}
+
+// 初始化
+func (p *RefundApproveAction) Init(ctx *quark.Context) interface{} {
+
+	// 文字
+	p.Name = "同意退款"
+
+	// 类型
+	p.Type = "link"
+
+	// 设置按钮大小,large | middle | small | default
+	p.Size = "small"
+
+	// 执行成功后刷新的组件
+	p.Reload = "table"
+
+	// 关闭时销毁 Modal 里的子元素
+	p.DestroyOnClose = true
+
+	// 设置展示位置
+	p.SetOnlyOnIndexTableRow(true)
+
+	// 行为接口接收的参数
+	p.SetApiParams([]string{
+		"id",
+	})
+
+	return p
+}
+
+// 字段
+func (p *RefundApproveAction) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Hidden("id", "ID"),
+
+		field.Number("refund_price", "退款金额").
+			SetPrecision(2).
+			SetHelp("默认为用户申请的退款金额，不能超过实付金额减去已退款金额").
+			SetRules([]rule.Rule{
+				rule.Required("请填写退款金额"),
+			}),
+	}
+}
+
+// 表单数据（异步获取）
+func (p *RefundApproveAction) Data(ctx *quark.Context) map[string]interface{} {
+	order, _ := service.NewOrderService().GetInfoById(ctx.Query("id"))
+
+	return map[string]interface{}{
+		"id":           ctx.Query("id"),
+		"refund_price": order.RefundPrice,
+	}
+}
+
+// 执行行为句柄
+func (p *RefundApproveAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	var param struct {
+		RefundPrice float64 `json:"refund_price"`
+	}
+	if err := ctx.Bind(&param); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	adminId, err := appservice.NewAuthService(ctx).GetAdminId()
+	if err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	order := model.Order{}
+	if err := query.First(&order).Error; err != nil {
+		return ctx.CJSONError("订单不存在")
+	}
+
+	refund, err := service.NewRefundService().Approve(order, adminId, param.RefundPrice)
+	if err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+	if refund.Status != model.OrderRefundStatusSuccess {
+		return ctx.CJSONOk("退款已提交，等待支付平台处理")
+	}
+
+	return ctx.CJSONOk("退款成功")
+}
+
+// 初始化
+func (p *RefundRejectAction) Init(ctx *quark.Context) interface{} {
+
+	// 文字
+	p.Name = "拒绝退款"
+
+	// 类型
+	p.Type = "link"
+
+	// 设置按钮大小,large | middle | small | default
+	p.Size = "small"
+
+	// 执行成功后刷新的组件
+	p.Reload = "table"
+
+	// 关闭时销毁 Modal 里的子元素
+	p.DestroyOnClose = true
+
+	// 设置展示位置
+	p.SetOnlyOnIndexTableRow(true)
+
+	// 行为接口接收的参数
+	p.SetApiParams([]string{
+		"id",
+	})
+
+	return p
+}
+
+// 字段
+func (p *RefundRejectAction) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Hidden("id", "ID"),
+
+		field.TextArea("reason", "拒绝原因").
+			SetRules([]rule.Rule{
+				rule.Required("请填写拒绝原因"),
+				rule.Max(500, "拒绝原因不能超过500个字符"),
+			}),
+	}
+}
+
+// 表单数据（异步获取）
+func (p *RefundRejectAction) Data(ctx *quark.Context) map[string]interface{} {
+	return map[string]interface{}{
+		"id": ctx.Query("id"),
+	}
+}
+
+// 执行行为句柄
+func (p *RefundRejectAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	var param struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&param); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	adminId, err := appservice.NewAuthService(ctx).GetAdminId()
+	if err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	order := model.Order{}
+	if err := query.First(&order).Error; err != nil {
+		return ctx.CJSONError("订单不存在")
+	}
+
+	if _, err := service.NewRefundService().Reject(order, adminId, param.Reason); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	return ctx.CJSONOk("操作成功")
+}
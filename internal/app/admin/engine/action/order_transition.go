@@ -0,0 +1,203 @@
+package action
+
+import (
+	"strings"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	appservice "github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource/actions"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/logistics"
+	"gorm.io/gorm"
+)
+
+// 订单状态流转，所有后台订单操作都通过订单状态机执行
+type OrderTransitionAction struct {
+	actions.ModalForm
+	Event          model.OrderEvent
+	ReasonRequired bool
+}
+
+type OrderShipAction struct {
+	OrderTransitionAction
+}
+
+type OrderReadyPickupAction struct {
+	OrderTransitionAction
+}
+
+type OrderCancelAction struct {
+	OrderTransitionAction
+}
+
+// 发货
+func OrderShip() *OrderShipAction {
+	return &OrderShipAction{
+		OrderTransitionAction{Event: model.OrderEventShip},
+	}
+}
+
+// 备货完成
+func OrderReadyPickup() *OrderReadyPickupAction {
+	return &OrderReadyPickupAction{
+		OrderTransitionAction{Event: model.OrderEventReadyPickup},
+	}
+}
+
+// 取消订单
+func OrderCancel() *OrderCancelAction {
+	return &OrderCancelAction{
+		OrderTransitionAction{Event: model.OrderEventCancel, ReasonRequired: true},
+	}
+}
+
+// 初始化
+func (p *OrderTransitionAction) Init(ctx *quark.Context) interface{} {
+
+	// 文字
+	p.Name = service.NewOrderService().GetEventName(p.Event)
+
+	// 类型
+	p.Type = "link"
+
+	// 设置按钮大小,large | middle | small | default
+	p.Size = "small"
+
+	// 执行成功后刷新的组件
+	p.Reload = "table"
+
+	// 关闭时销毁 Modal 里的子元素
+	p.DestroyOnClose = true
+
+	// 设置展示位置
+	p.SetOnlyOnIndexTableRow(true)
+
+	// 行为接口接收的参数
+	p.SetApiParams([]string{
+		"id",
+	})
+
+	return p
+}
+
+// 字段
+func (p *OrderTransitionAction) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	reason := field.TextArea("reason", "操作原因").
+		SetRules([]rule.Rule{
+			rule.Max(500, "操作原因不能超过500个字符"),
+		})
+	if p.ReasonRequired {
+		reason.SetRules([]rule.Rule{
+			rule.Required("请填写操作原因"),
+			rule.Max(500, "操作原因不能超过500个字符"),
+		})
+	}
+
+	return []interface{}{
+		field.Hidden("id", "ID"),
+		reason,
+	}
+}
+
+// 表单数据（异步获取）
+func (p *OrderTransitionAction) Data(ctx *quark.Context) map[string]interface{} {
+	return map[string]interface{}{
+		"id": ctx.Query("id"),
+	}
+}
+
+// 执行行为句柄
+func (p *OrderTransitionAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	var param struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&param); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+	if p.ReasonRequired && param.Reason == "" {
+		return ctx.CJSONError("请填写操作原因")
+	}
+
+	return p.transition(ctx, query, param.Reason, nil)
+}
+
+// 批量流转订单状态
+func (p *OrderTransitionAction) transition(ctx *quark.Context, query *gorm.DB, reason string, data map[string]interface{}) error {
+	adminId, err := appservice.NewAuthService(ctx).GetAdminId()
+	if err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+
+	orders := []model.Order{}
+	if err := query.Find(&orders).Error; err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+	if len(orders) == 0 {
+		return ctx.CJSONError("订单不存在")
+	}
+
+	for _, order := range orders {
+		_, err := service.NewOrderService().Transition(dto.OrderTransitionDTO{
+			OrderId:   order.Id,
+			Event:     p.Event,
+			ActorType: model.OrderActorAdmin,
+			ActorId:   adminId,
+			Reason:    reason,
+			Data:      data,
+		})
+		if err != nil {
+			return ctx.CJSONError("订单" + order.OrderNo + "：" + err.Error())
+		}
+	}
+
+	return ctx.CJSONOk("操作成功")
+}
+
+// 字段
+func (p *OrderShipAction) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Hidden("id", "ID"),
+
+		field.Select("delivery_code", "快递公司").
+			SetOptions(service.NewLogisticsService().CarrierOptions()).
+			SetRules([]rule.Rule{
+				rule.Required("请选择快递公司"),
+			}),
+
+		field.Text("delivery_no", "快递单号").
+			SetRules([]rule.Rule{
+				rule.Required("请填写快递单号"),
+				rule.Max(64, "快递单号不能超过64个字符"),
+			}),
+	}
+}
+
+// 执行行为句柄
+func (p *OrderShipAction) Handle(ctx *quark.Context, query *gorm.DB) error {
+	var param struct {
+		DeliveryCode string `json:"delivery_code"`
+		DeliveryNo   string `json:"delivery_no"`
+	}
+	if err := ctx.Bind(&param); err != nil {
+		return ctx.CJSONError(err.Error())
+	}
+	param.DeliveryNo = strings.TrimSpace(param.DeliveryNo)
+	if param.DeliveryCode == "" || param.DeliveryNo == "" {
+		return ctx.CJSONError("请填写快递公司和快递单号")
+	}
+
+	deliveryName := logistics.GetCarrierName(param.DeliveryCode)
+	return p.transition(ctx, query, deliveryName+" "+param.DeliveryNo, map[string]interface{}{
+		"delivery_code": param.DeliveryCode,
+		"delivery_name": deliveryName,
+		"delivery_no":   param.DeliveryNo,
+	})
+}
@@ -19,6 +19,19 @@ var Providers = []interface{}{
 	&resource.Banner{},
 	&resource.BannerCategory{},
 	&resource.Navigation{},
+	&resource.Item{},
+	&resource.ItemCategory{},
+	&resource.Order{},
+	&resource.Refund{},
+	&resource.PayReconciliation{},
+	&resource.Bill{},
+	&resource.BillRecord{},
+	&resource.Clerk{},
+	&resource.Merchant{},
+	&resource.Coupon{},
+	&resource.UserCoupon{},
+	&resource.Job{},
+	&resource.JobRun{},
 	&upload.File{},
 	&upload.Image{},
 }
@@ -17,7 +17,7 @@ import (
 )
 
 type Article struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -37,7 +37,7 @@ func (p *Article) Init(ctx *quark.Context) interface{} {
 
 // 只查询文章类型
 func (p *Article) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
-	return query.Where("type", "ARTICLE")
+	return p.PlatformTemplate.Query(ctx, query).Where("type", "ARTICLE")
 }
 
 func (p *Article) Fields(ctx *quark.Context) []interface{} {
@@ -170,6 +170,10 @@ func (p *Article) ExtendFields(ctx *quark.Context) []interface{} {
 		field.File("file_ids", "附件").
 			OnlyOnForms(),
 
+		field.Text("page_tpl", "详情模板").
+			SetHelp("为空时使用分类的详情模板").
+			OnlyOnForms(),
+
 		field.Switch("comment_status", "允许评论").
 			SetEditable(true).
 			SetTrueValue("正常").
@@ -230,6 +234,11 @@ func (p *Article) BeforeEditing(request *quark.Context, data map[string]interfac
 
 // 保存数据前回调
 func (p *Article) BeforeSaving(ctx *quark.Context, submitData map[string]interface{}) (map[string]interface{}, error) {
+	submitData, err := p.PlatformTemplate.BeforeSaving(ctx, submitData)
+	if err != nil {
+		return submitData, err
+	}
+
 	if int(submitData["show_type"].(float64)) == 2 {
 		submitData["cover_ids"] = submitData["single_cover_ids"]
 	}
@@ -11,7 +11,7 @@ import (
 )
 
 type Banner struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -10,7 +10,7 @@ import (
 )
 
 type BannerCategory struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -0,0 +1,112 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type Bill struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *Bill) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "账单明细"
+
+	// 模型
+	p.Model = &model.Bill{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，账单只追加不修改，不提供编辑
+func (p *Bill) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("bill_no", "账单号"),
+
+		field.Number("uid", "用户ID"),
+
+		field.Text("title", "标题"),
+
+		field.Select("category", "类别").
+			SetOptions(p.categoryOptions()),
+
+		field.Select("type", "明细类型").
+			SetOptions(p.typeOptions()),
+
+		field.Select("pm", "收支").
+			SetOptions([]selectfield.Option{
+				field.SelectOption("支出", model.BillPMExpense),
+				field.SelectOption("收入", model.BillPMIncome),
+			}),
+
+		field.Number("number", "金额"),
+
+		field.Number("balance", "记账后余额"),
+
+		field.Text("link_id", "关联ID").
+			OnlyOnDetail(),
+
+		field.Text("mark", "备注").
+			OnlyOnDetail(),
+
+		field.Datetime("created_at", "记账时间"),
+	}
+}
+
+// 搜索
+func (p *Bill) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("uid", "用户ID"),
+		searches.Input("bill_no", "账单号"),
+		searches.Select("category", "类别").SetOptions(p.categoryOptions()),
+		searches.Select("type", "明细类型").SetOptions(p.typeOptions()),
+		searches.Select("pm", "收支").SetOptions([]selectfield.Option{
+			{Label: "支出", Value: model.BillPMExpense},
+			{Label: "收入", Value: model.BillPMIncome},
+		}),
+		searches.DatetimeRange("created_at", "记账时间"),
+	}
+}
+
+// 行为
+func (p *Bill) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.DetailLink(),
+	}
+}
+
+// 类别选项
+func (p *Bill) categoryOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "余额", Value: model.BillCategoryNowMoney},
+		{Label: "在线支付", Value: model.BillCategoryPay},
+	}
+}
+
+// 明细类型选项
+func (p *Bill) typeOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "充值", Value: model.BillTypeRecharge},
+		{Label: "购买商品", Value: model.BillTypePayOrder},
+		{Label: "订单退款", Value: model.BillTypePayRefund},
+		{Label: "系统增加", Value: model.BillTypeSystemAdd},
+		{Label: "系统扣减", Value: model.BillTypeSystemSub},
+	}
+}
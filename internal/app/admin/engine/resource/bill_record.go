@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type BillRecord struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *BillRecord) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "账单汇总"
+
+	// 模型
+	p.Model = &model.BillRecord{}
+
+	// 默认排序
+	p.IndexQueryOrder = "day desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，汇总数据由定时任务生成，不提供编辑
+func (p *BillRecord) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("title", "标题"),
+
+		field.Text("day", "日期"),
+
+		field.Number("entry_price", "支付金额"),
+
+		field.Number("exp_price", "退款金额"),
+
+		field.Number("income_price", "净收入"),
+
+		field.Datetime("updated_at", "汇总时间"),
+	}
+}
+
+// 搜索
+func (p *BillRecord) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("day", "日期"),
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type Category struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -39,7 +39,7 @@ func (p *Category) Init(ctx *quark.Context) interface{} {
 
 // 全局查询
 func (p *Category) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
-	return query.Where("type = ?", "ARTICLE")
+	return p.PlatformTemplate.Query(ctx, query).Where("type = ?", "ARTICLE")
 }
 
 func (p *Category) Fields(ctx *quark.Context) []interface{} {
@@ -114,7 +114,7 @@ func (p *Category) ExtendFields(ctx *quark.Context) []interface{} {
 		field.Text("index_tpl", "频道模板").
 			OnlyOnForms(),
 
-		field.Text("lists_tpl", "列表模板").
+		field.Text("list_tpl", "列表模板").
 			OnlyOnForms(),
 
 		field.Text("detail_tpl", "详情模板").
@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type Clerk struct {
+	MerchantTemplate
+}
+
+// 初始化
+func (p *Clerk) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "核销员"
+
+	// 模型
+	p.Model = &model.Clerk{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+func (p *Clerk) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Number("uid", "用户ID").
+			SetHelp("核销员登录小程序后使用的用户ID").
+			SetRules([]rule.Rule{
+				rule.Required("用户ID必须填写"),
+			}).
+			SetCreationRules([]rule.Rule{
+				rule.Unique("clerks", "uid", "该用户已是核销员"),
+			}).
+			SetUpdateRules([]rule.Rule{
+				rule.Unique("clerks", "uid", "{id}", "该用户已是核销员"),
+			}),
+
+		field.Text("name", "姓名").
+			SetRules([]rule.Rule{
+				rule.Required("姓名必须填写"),
+			}),
+
+		field.Text("phone", "手机号"),
+
+		p.MerchantField(ctx),
+
+		field.Switch("status", "状态").
+			SetEditable(true).
+			SetTrueValue("正常").
+			SetFalseValue("禁用").
+			SetDefault(true),
+
+		field.Datetime("created_at", "创建时间").
+			OnlyOnIndex(),
+	}
+}
+
+// 搜索
+func (p *Clerk) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("name", "姓名"),
+		searches.Input("phone", "手机号"),
+		searches.Status(),
+	}
+}
+
+// 行为
+func (p *Clerk) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.CreateLink(),
+		actions.BatchDelete(),
+		actions.BatchDisable(),
+		actions.BatchEnable(),
+		actions.EditLink(),
+		actions.Delete(),
+		actions.FormSubmit(),
+		actions.FormReset(),
+		actions.FormBack(),
+		actions.FormExtraBack(),
+	}
+}
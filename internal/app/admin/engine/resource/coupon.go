@@ -0,0 +1,152 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/app/admin/engine/action"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+type Coupon struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *Coupon) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "优惠券"
+
+	// 模型
+	p.Model = &model.Coupon{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+func (p *Coupon) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	// 分类列表
+	categories, _ := service.NewCategoryService().GetList("ITEM")
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("title", "名称").
+			SetRules([]rule.Rule{
+				rule.Required("名称必须填写"),
+			}),
+
+		field.Radio("type", "类型").
+			SetOptions(p.typeOptions()).
+			SetDefault(model.CouponTypeFixed),
+
+		field.Number("amount", "减免金额").
+			SetPrecision(2).
+			SetHelp("满减券使用"),
+
+		field.Number("rate", "折扣").
+			SetHelp("折扣券使用，按百分比填写，如85表示85折").
+			SetDefault(100).
+			OnlyOnForms(),
+
+		field.Number("min_price", "使用门槛").
+			SetPrecision(2).
+			SetHelp("适用商品金额满多少可用，0为无门槛"),
+
+		field.Number("max_discount", "最高优惠").
+			SetPrecision(2).
+			SetHelp("折扣券的优惠上限，0为不限").
+			OnlyOnForms(),
+
+		field.TreeSelect("category_ids", "适用分类").
+			SetTreeData(categories, "pid", "title", "id").
+			SetMultiple(true).
+			SetHelp("不选则全部商品可用").
+			OnlyOnForms(),
+
+		field.Number("total", "发放总量").
+			SetHelp("0为不限量"),
+
+		field.Number("issued", "已发放").
+			OnlyOnIndex(),
+
+		field.Number("per_limit", "每人限领").
+			SetDefault(1).
+			OnlyOnForms(),
+
+		field.Number("valid_days", "有效天数").
+			SetHelp("自领取之日起计算").
+			SetDefault(30).
+			SetRules([]rule.Rule{
+				rule.Required("有效天数必须填写"),
+			}),
+
+		field.Datetime("claim_start_time", "领取开始时间").
+			OnlyOnForms(),
+
+		field.Datetime("claim_end_time", "领取结束时间").
+			OnlyOnForms(),
+
+		field.Switch("is_public", "公开领取").
+			SetHelp("关闭后只能由管理员发放").
+			SetTrueValue("是").
+			SetFalseValue("否").
+			SetDefault(true).
+			OnlyOnForms(),
+
+		field.Switch("status", "状态").
+			SetEditable(true).
+			SetTrueValue("正常").
+			SetFalseValue("禁用").
+			SetDefault(true),
+
+		field.Datetime("created_at", "创建时间").
+			OnlyOnIndex(),
+	}
+}
+
+// 搜索
+func (p *Coupon) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("title", "名称"),
+		searches.Select("type", "类型").SetOptions(p.typeOptions()),
+		searches.Status(),
+	}
+}
+
+// 行为
+func (p *Coupon) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.CreateLink(),
+		actions.BatchDelete(),
+		actions.BatchDisable(),
+		actions.BatchEnable(),
+		action.CouponIssue(),
+		actions.EditLink(),
+		actions.Delete(),
+		actions.FormSubmit(),
+		actions.FormReset(),
+		actions.FormBack(),
+		actions.FormExtraBack(),
+	}
+}
+
+// 类型选项
+func (p *Coupon) typeOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "满减券", Value: model.CouponTypeFixed},
+		{Label: "折扣券", Value: model.CouponTypePercent},
+	}
+}
@@ -0,0 +1,310 @@
+package resource
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/radio"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/tabs"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-go/v3/utils/convert"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"gorm.io/gorm"
+)
+
+type Item struct {
+	MerchantTemplate
+}
+
+// 商品规格表单数据
+type itemSpecForm struct {
+	SpecType   uint8              `json:"spec_type"`
+	Price      float64            `json:"price"`
+	OtPrice    float64            `json:"ot_price"`
+	Cost       float64            `json:"cost"`
+	Stock      int                `json:"stock"`
+	Attrs      []dto.ItemAttrDTO  `json:"attrs"`
+	AttrValues []dto.AttrValueDTO `json:"attr_values"`
+}
+
+// 初始化
+func (p *Item) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "商品"
+
+	// 模型
+	p.Model = &model.Item{}
+
+	// 默认排序
+	p.IndexQueryOrder = "sort asc, id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+func (p *Item) Fields(ctx *quark.Context) []interface{} {
+	var tabPanes []interface{}
+
+	// 基础字段
+	basePane := (&tabs.TabPane{}).
+		Init().
+		SetTitle("基础").
+		SetBody(p.BaseFields(ctx))
+	tabPanes = append(tabPanes, basePane)
+
+	// 规格字段
+	specPane := (&tabs.TabPane{}).
+		Init().
+		SetTitle("规格").
+		SetBody(p.SpecFields(ctx))
+	tabPanes = append(tabPanes, specPane)
+
+	// 扩展字段
+	extendPane := (&tabs.TabPane{}).
+		Init().
+		SetTitle("扩展").
+		SetBody(p.ExtendFields(ctx))
+	tabPanes = append(tabPanes, extendPane)
+
+	return tabPanes
+}
+
+// 基础字段
+func (p *Item) BaseFields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	// 分类列表
+	categories, _ := service.NewCategoryService().GetList("ITEM")
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Image("image", "商品图").
+			SetMode("single").
+			SetRules([]rule.Rule{
+				rule.Required("请上传商品图"),
+			}),
+
+		field.Text("name", "名称").
+			SetRules([]rule.Rule{
+				rule.Required("名称必须填写"),
+			}),
+
+		p.MerchantField(ctx),
+
+		field.TreeSelect("category_ids", "商品分类").
+			SetTreeData(categories, "pid", "title", "id").
+			SetMultiple(true).
+			SetRules([]rule.Rule{
+				rule.Required("请选择商品分类"),
+			}).
+			OnlyOnForms(),
+
+		field.Text("keyword", "关键字").
+			OnlyOnForms(),
+
+		field.TextArea("description", "简介").
+			SetRules([]rule.Rule{
+				rule.Max(500, "简介不能超过500个字符"),
+			}).
+			OnlyOnForms(),
+
+		field.Image("slider_image", "轮播图").
+			SetMode("multiple").
+			OnlyOnForms(),
+
+		field.Number("price", "价格").
+			OnlyOnIndex(),
+
+		field.Number("sales", "销量").
+			OnlyOnIndex(),
+
+		field.Number("stock", "库存").
+			OnlyOnIndex(),
+
+		field.Number("sort", "排序").
+			SetEditable(true).
+			SetDefault(0),
+
+		field.Switch("status", "上架").
+			SetTrueValue("上架").
+			SetFalseValue("下架").
+			SetEditable(true).
+			SetDefault(true),
+	}
+}
+
+// 规格字段
+func (p *Item) SpecFields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Radio("spec_type", "规格类型").
+			SetOptions([]radio.Option{
+				field.RadioOption("单规格", 0),
+				field.RadioOption("多规格", 1),
+			}).
+			SetWhen(0, func() interface{} {
+				return []interface{}{
+					field.Number("price", "售价").
+						SetPrecision(2).
+						SetMin(0).
+						OnlyOnForms(),
+
+					field.Number("ot_price", "原价").
+						SetPrecision(2).
+						SetMin(0).
+						OnlyOnForms(),
+
+					field.Number("cost", "成本价").
+						SetPrecision(2).
+						SetMin(0).
+						OnlyOnForms(),
+
+					field.Number("stock", "库存").
+						SetMin(0).
+						OnlyOnForms(),
+				}
+			}).
+			SetWhen(1, func() interface{} {
+				return []interface{}{
+					field.Sku("attr_values", "商品属性").
+						SetAttributesName("attrs").
+						OnlyOnForms(),
+				}
+			}).
+			SetDefault(0).
+			OnlyOnForms(),
+	}
+}
+
+// 扩展字段
+func (p *Item) ExtendFields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.Number("ficti_sales", "虚拟销量").
+			SetDefault(0).
+			OnlyOnForms(),
+
+		field.Number("ficti_views", "虚拟浏览量").
+			SetDefault(0).
+			OnlyOnForms(),
+
+		field.Editor("content", "商品详情").
+			OnlyOnForms(),
+	}
+}
+
+// 搜索
+func (p *Item) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("name", "名称"),
+		searches.Status(),
+		searches.DatetimeRange("created_at", "创建时间"),
+	}
+}
+
+// 行为
+func (p *Item) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.CreateLink(),
+		actions.BatchDelete(),
+		actions.BatchDisable(),
+		actions.BatchEnable(),
+		actions.EditLink(),
+		actions.Delete(),
+		actions.FormSubmit(),
+		actions.FormReset(),
+		actions.FormBack(),
+		actions.FormExtraBack(),
+	}
+}
+
+// 编辑页面显示前回调
+func (p *Item) BeforeEditing(request *quark.Context, data map[string]interface{}) map[string]interface{} {
+	itemId, err := strconv.Atoi(convert.AnyToString(data["id"]))
+	if err != nil {
+		return data
+	}
+
+	// 商品规格
+	attrs := []map[string]interface{}{}
+	itemAttrs, _ := service.NewItemService().GetAttrs(itemId)
+	for _, attr := range itemAttrs {
+		items := []map[string]interface{}{}
+		for _, value := range attr.AttrItems.([]string) {
+			items = append(items, map[string]interface{}{"name": value})
+		}
+		attrs = append(attrs, map[string]interface{}{
+			"name":  attr.AttrName,
+			"items": items,
+		})
+	}
+	data["attrs"] = attrs
+
+	// 商品规格属性值，需要将规格值平铺到每一行中
+	attrValues := []map[string]interface{}{}
+	itemAttrValues, _ := service.NewItemService().GetAttrValues(itemId, false)
+	for _, attrValue := range itemAttrValues {
+		row := map[string]interface{}{
+			"suk":        attrValue.Suk,
+			"attr_value": attrValue.AttrValue,
+			"image":      attrValue.Image,
+			"price":      attrValue.Price,
+			"cost":       attrValue.Cost,
+			"ot_price":   attrValue.OtPrice,
+			"stock":      attrValue.Stock,
+			"is_default": attrValue.IsDefault,
+			"status":     attrValue.Status,
+		}
+		if values, ok := attrValue.AttrValue.(map[string]interface{}); ok {
+			for key, value := range values {
+				row[key] = value
+			}
+		}
+		attrValues = append(attrValues, row)
+	}
+	data["attr_values"] = attrValues
+
+	return data
+}
+
+// 保存数据后回调
+func (p *Item) AfterSaved(ctx *quark.Context, id int, data map[string]interface{}, result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	form := itemSpecForm{}
+	formBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(formBytes, &form); err != nil {
+		return err
+	}
+
+	// 单规格商品使用表单中的价格与库存
+	if form.SpecType != 1 {
+		form.AttrValues = []dto.AttrValueDTO{
+			{
+				Price:   form.Price,
+				OtPrice: form.OtPrice,
+				Cost:    form.Cost,
+				Stock:   form.Stock,
+			},
+		}
+	}
+
+	return service.NewItemService().SaveSpecs(id, form.SpecType, form.Attrs, form.AttrValues)
+}
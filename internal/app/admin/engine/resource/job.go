@@ -0,0 +1,101 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/app/admin/engine/action"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type Job struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *Job) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "定时任务"
+
+	// 模型
+	p.Model = &model.Job{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id asc"
+
+	// 分页
+	p.PageSize = 20
+
+	return p
+}
+
+// 字段，任务在代码中注册，只允许启用、禁用
+func (p *Job) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("name", "任务名称"),
+
+		field.Text("title", "说明"),
+
+		field.Text("spec", "执行周期"),
+
+		field.Number("timeout", "超时时间(秒)").
+			OnlyOnDetail(),
+
+		field.Select("singleton", "单例模式").
+			SetOptions([]selectfield.Option{
+				field.SelectOption("否", 0),
+				field.SelectOption("是", 1),
+			}).
+			OnlyOnDetail(),
+
+		field.Datetime("last_run_at", "最近执行时间"),
+
+		field.Select("last_status", "最近执行结果").
+			SetOptions(runStatusOptions()),
+
+		field.Number("last_duration", "耗时(毫秒)"),
+
+		field.TextArea("last_error", "错误信息").
+			OnlyOnDetail(),
+
+		field.Switch("status", "状态").
+			SetEditable(true).
+			SetTrueValue("启用").
+			SetFalseValue("禁用"),
+	}
+}
+
+// 搜索
+func (p *Job) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("name", "任务名称"),
+		searches.Select("last_status", "最近执行结果").SetOptions(runStatusOptions()),
+		searches.Status(),
+	}
+}
+
+// 行为
+func (p *Job) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.BatchDisable(),
+		actions.BatchEnable(),
+		action.JobRunNow(),
+		actions.DetailLink(),
+	}
+}
+
+// 执行结果选项
+func runStatusOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "未执行", Value: model.JobRunStatusNone},
+		{Label: "成功", Value: model.JobRunStatusSuccess},
+		{Label: "失败", Value: model.JobRunStatusFailed},
+	}
+}
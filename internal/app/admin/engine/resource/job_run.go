@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/scheduler"
+)
+
+type JobRun struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *JobRun) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "任务日志"
+
+	// 模型
+	p.Model = &model.JobRun{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，执行记录由调度器生成，不提供编辑
+func (p *JobRun) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("job_name", "任务名称"),
+
+		field.Select("trigger_type", "触发方式").
+			SetOptions(p.triggerOptions()),
+
+		field.Select("status", "执行结果").
+			SetOptions(runStatusOptions()),
+
+		field.Datetime("started_at", "开始时间"),
+
+		field.Number("duration", "耗时(毫秒)"),
+
+		field.TextArea("error", "错误信息").
+			OnlyOnDetail(),
+	}
+}
+
+// 搜索
+func (p *JobRun) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("job_name", "任务名称"),
+		searches.Select("trigger_type", "触发方式").SetOptions(p.triggerOptions()),
+		searches.Select("status", "执行结果").SetOptions(runStatusOptions()),
+		searches.DatetimeRange("started_at", "开始时间"),
+	}
+}
+
+// 行为
+func (p *JobRun) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.DetailLink(),
+	}
+}
+
+// 触发方式选项
+func (p *JobRun) triggerOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "定时触发", Value: scheduler.TriggerCron},
+		{Label: "手动触发", Value: scheduler.TriggerManual},
+	}
+}
@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/rule"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type Merchant struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *Merchant) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "商户"
+
+	// 模型
+	p.Model = &model.Merchant{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+func (p *Merchant) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Image("logo", "Logo").
+			SetMode("single").
+			OnlyOnForms(),
+
+		field.Text("name", "名称").
+			SetRules([]rule.Rule{
+				rule.Required("名称必须填写"),
+			}),
+
+		field.Text("contact", "联系人"),
+
+		field.Text("phone", "联系电话"),
+
+		field.Text("address", "地址").
+			OnlyOnForms(),
+
+		field.Number("admin_id", "管理员ID").
+			SetHelp("绑定后该管理员只能管理本商户的商品、订单等数据").
+			SetRules([]rule.Rule{
+				rule.Required("管理员ID必须填写"),
+			}).
+			SetCreationRules([]rule.Rule{
+				rule.Unique("merchants", "admin_id", "该管理员已绑定其他商户"),
+			}).
+			SetUpdateRules([]rule.Rule{
+				rule.Unique("merchants", "admin_id", "{id}", "该管理员已绑定其他商户"),
+			}),
+
+		field.Switch("status", "状态").
+			SetEditable(true).
+			SetTrueValue("正常").
+			SetFalseValue("禁用").
+			SetDefault(true),
+
+		field.Datetime("created_at", "创建时间").
+			OnlyOnIndex(),
+	}
+}
+
+// 搜索
+func (p *Merchant) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("name", "名称"),
+		searches.Input("phone", "联系电话"),
+		searches.Status(),
+	}
+}
+
+// 行为
+func (p *Merchant) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.CreateLink(),
+		actions.BatchDelete(),
+		actions.BatchDisable(),
+		actions.BatchEnable(),
+		actions.EditLink(),
+		actions.Delete(),
+		actions.FormSubmit(),
+		actions.FormReset(),
+		actions.FormBack(),
+		actions.FormExtraBack(),
+	}
+}
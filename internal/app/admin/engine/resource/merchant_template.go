@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	appservice "github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"gorm.io/gorm"
+)
+
+// 商户数据资源，商户管理员只能查看和维护本商户的数据，平台管理员可以查看全部数据
+type MerchantTemplate struct {
+	resource.Template
+}
+
+// 平台资源，只有平台管理员可以访问
+type PlatformTemplate struct {
+	resource.Template
+}
+
+// 获取当前管理员所属的商户id，平台管理员返回0
+//
+// 获取失败或所属商户已禁用、已删除时返回错误，调用方需拒绝访问
+func getAdminMerchantId(ctx *quark.Context) (int, error) {
+	adminId, err := appservice.NewAuthService(ctx).GetAdminId()
+	if err != nil {
+		return 0, err
+	}
+	merchantId, _, err := service.NewMerchantService().GetMerchantIdByAdminId(adminId)
+	return merchantId, err
+}
+
+// 全局查询，按商户过滤
+func (p *MerchantTemplate) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
+	merchantId, err := getAdminMerchantId(ctx)
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	if merchantId > 0 {
+		return query.Where("merchant_id = ?", merchantId)
+	}
+	return query
+}
+
+// 保存数据前回调，商户管理员保存的数据归属于本商户
+func (p *MerchantTemplate) BeforeSaving(ctx *quark.Context, submitData map[string]interface{}) (map[string]interface{}, error) {
+	merchantId, err := getAdminMerchantId(ctx)
+	if err != nil {
+		return submitData, err
+	}
+	if merchantId > 0 {
+		submitData["merchant_id"] = merchantId
+	}
+	return submitData, nil
+}
+
+// 所属商户字段，只有平台管理员可以选择，商户管理员保存时自动归属本商户
+func (p *MerchantTemplate) MerchantField(ctx *quark.Context) interface{} {
+	field := &resource.Field{}
+	if merchantId, err := getAdminMerchantId(ctx); err != nil || merchantId > 0 {
+		return field.Hidden("merchant_id", "所属商户")
+	}
+
+	return field.Select("merchant_id", "所属商户").
+		SetOptions(service.NewMerchantService().Options()).
+		SetDefault(0)
+}
+
+// 全局查询，商户管理员无权访问
+func (p *PlatformTemplate) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
+	merchantId, err := getAdminMerchantId(ctx)
+	if err != nil {
+		query.AddError(err)
+	} else if merchantId > 0 {
+		query.AddError(errors.New("商户管理员无权访问平台数据"))
+	}
+	return query
+}
+
+// 保存数据前回调，商户管理员无权修改
+func (p *PlatformTemplate) BeforeSaving(ctx *quark.Context, submitData map[string]interface{}) (map[string]interface{}, error) {
+	merchantId, err := getAdminMerchantId(ctx)
+	if err != nil {
+		return submitData, err
+	}
+	if merchantId > 0 {
+		return submitData, errors.New("商户管理员无权修改平台数据")
+	}
+	return submitData, nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type Navigation struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/app/admin/engine/action"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+type Order struct {
+	MerchantTemplate
+}
+
+// 初始化
+func (p *Order) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "订单"
+
+	// 模型
+	p.Model = &model.Order{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，订单状态只能通过行为流转，不提供编辑
+func (p *Order) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("order_no", "订单号"),
+
+		field.Text("realname", "收货人"),
+
+		field.Text("user_phone", "联系电话"),
+
+		field.Text("user_address", "收货地址").
+			OnlyOnDetail(),
+
+		field.Number("total_num", "商品数量"),
+
+		field.Number("pay_price", "实付金额"),
+
+		p.MerchantField(ctx),
+
+		field.Select("shipping_type", "配送方式").
+			SetOptions([]selectfield.Option{
+				field.SelectOption("快递配送", uint8(model.ShippingTypeExpress)),
+				field.SelectOption("门店自提", uint8(model.ShippingTypePickup)),
+			}),
+
+		field.Select("status", "订单状态").
+			SetOptions(service.NewOrderService().StatusOptions()),
+
+		field.Select("refund_status", "退款状态").
+			SetOptions(service.NewOrderService().RefundStatusOptions()),
+
+		field.Text("delivery_name", "快递公司").
+			OnlyOnDetail(),
+
+		field.Text("delivery_no", "快递单号").
+			OnlyOnDetail(),
+
+		field.Text("remark", "备注").
+			OnlyOnDetail(),
+
+		field.Datetime("pay_time", "支付时间").
+			OnlyOnDetail(),
+
+		field.Datetime("created_at", "下单时间"),
+	}
+}
+
+// 搜索
+func (p *Order) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("order_no", "订单号"),
+		searches.Input("user_phone", "联系电话"),
+		searches.Select("status", "订单状态").SetOptions(service.NewOrderService().StatusOptions()),
+		searches.Select("refund_status", "退款状态").SetOptions(service.NewOrderService().RefundStatusOptions()),
+		searches.DatetimeRange("created_at", "下单时间"),
+	}
+}
+
+// 行为
+func (p *Order) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		action.OrderShip(),
+		action.OrderReadyPickup(),
+		action.OrderCancel(),
+		actions.DetailLink(),
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type Page struct {
-	resource.Template
+	PlatformTemplate
 }
 
 // 初始化
@@ -36,7 +36,7 @@ func (p *Page) Init(ctx *quark.Context) interface{} {
 
 // 只查询单页类型
 func (p *Page) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
-	return query.Where("type", "PAGE")
+	return p.PlatformTemplate.Query(ctx, query).Where("type", "PAGE")
 }
 
 // 字段
@@ -75,6 +75,10 @@ func (p *Page) Fields(ctx *quark.Context) []interface{} {
 
 		field.Editor("content", "内容").OnlyOnForms(),
 
+		field.Text("page_tpl", "页面模板").
+			SetHelp("为空时使用 page.html").
+			OnlyOnForms(),
+
 		field.Datetime("created_at", "创建时间").OnlyOnIndex(),
 
 		field.Switch("status", "状态").
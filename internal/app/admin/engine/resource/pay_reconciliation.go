@@ -0,0 +1,94 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+)
+
+type PayReconciliation struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *PayReconciliation) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "支付对账"
+
+	// 模型
+	p.Model = &model.PayReconciliation{}
+
+	// 默认排序
+	p.IndexQueryOrder = "bill_date desc, id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，对账报告由定时任务生成，不提供编辑
+func (p *PayReconciliation) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Select("channel", "支付渠道").
+			SetOptions(p.channelOptions()),
+
+		field.Text("bill_date", "账单日期"),
+
+		field.Number("local_count", "本地笔数"),
+
+		field.Number("local_amount", "本地金额"),
+
+		field.Number("remote_count", "平台笔数"),
+
+		field.Number("remote_amount", "平台金额"),
+
+		field.Number("diff_count", "差异笔数"),
+
+		field.TextArea("diffs", "差异明细").
+			OnlyOnDetail(),
+
+		field.Select("status", "对账结果").
+			SetOptions([]selectfield.Option{
+				field.SelectOption("存在差异", 0),
+				field.SelectOption("平账", 1),
+			}),
+
+		field.Datetime("updated_at", "对账时间"),
+	}
+}
+
+// 搜索
+func (p *PayReconciliation) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Select("channel", "支付渠道").SetOptions(p.channelOptions()),
+		searches.Select("status", "对账结果").SetOptions([]selectfield.Option{
+			{Label: "存在差异", Value: 0},
+			{Label: "平账", Value: 1},
+		}),
+	}
+}
+
+// 行为
+func (p *PayReconciliation) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.DetailLink(),
+	}
+}
+
+// 支付渠道选项
+func (p *PayReconciliation) channelOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "微信支付", Value: pay.ChannelWechat},
+		{Label: "支付宝", Value: pay.ChannelAlipay},
+	}
+}
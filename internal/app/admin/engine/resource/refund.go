@@ -0,0 +1,100 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/app/admin/engine/action"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"gorm.io/gorm"
+)
+
+type Refund struct {
+	MerchantTemplate
+}
+
+// 初始化
+func (p *Refund) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "退款审核"
+
+	// 模型
+	p.Model = &model.Order{}
+
+	// 默认排序
+	p.IndexQueryOrder = "refund_reason_time desc, id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 只查询申请过退款的订单
+func (p *Refund) Query(ctx *quark.Context, query *gorm.DB) *gorm.DB {
+	return p.MerchantTemplate.Query(ctx, query).Where("refund_status <> ?", model.RefundStatusNone)
+}
+
+// 字段
+func (p *Refund) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Text("order_no", "订单号"),
+
+		field.Text("realname", "收货人"),
+
+		field.Text("user_phone", "联系电话"),
+
+		field.Number("pay_price", "实付金额"),
+
+		field.Number("refund_price", "申请退款金额"),
+
+		field.Number("refunded_price", "已退款金额"),
+
+		field.Text("pay_type", "支付方式"),
+
+		field.Select("status", "订单状态").
+			SetOptions(service.NewOrderService().StatusOptions()),
+
+		field.Select("refund_status", "退款状态").
+			SetOptions(service.NewOrderService().RefundStatusOptions()),
+
+		field.Text("refund_reason", "退款原因"),
+
+		field.Text("refund_reason_explain", "退款说明").
+			OnlyOnDetail(),
+
+		field.Text("refund_reason_img", "退款凭证").
+			OnlyOnDetail(),
+
+		field.Text("refund_rejection_reason", "拒绝原因").
+			OnlyOnDetail(),
+
+		field.Datetime("refund_reason_time", "申请时间"),
+	}
+}
+
+// 搜索
+func (p *Refund) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("order_no", "订单号"),
+		searches.Input("user_phone", "联系电话"),
+		searches.Select("refund_status", "退款状态").SetOptions(service.NewOrderService().RefundStatusOptions()),
+		searches.DatetimeRange("refund_reason_time", "申请时间"),
+	}
+}
+
+// 行为
+func (p *Refund) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		action.RefundApprove(),
+		action.RefundReject(),
+		actions.DetailLink(),
+	}
+}
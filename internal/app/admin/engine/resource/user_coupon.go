@@ -0,0 +1,92 @@
+package resource
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/actions"
+	"github.com/quarkcloudio/quark-go/v3/app/admin/searches"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/resource"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type UserCoupon struct {
+	PlatformTemplate
+}
+
+// 初始化
+func (p *UserCoupon) Init(ctx *quark.Context) interface{} {
+
+	// 标题
+	p.Title = "领取记录"
+
+	// 模型
+	p.Model = &model.UserCoupon{}
+
+	// 默认排序
+	p.IndexQueryOrder = "id desc"
+
+	// 分页
+	p.PageSize = 10
+
+	return p
+}
+
+// 字段，领取记录由用户领取或管理员发放产生，不提供编辑
+func (p *UserCoupon) Fields(ctx *quark.Context) []interface{} {
+	field := &resource.Field{}
+
+	return []interface{}{
+		field.ID("id", "ID"),
+
+		field.Number("uid", "用户ID"),
+
+		field.Number("coupon_id", "优惠券ID"),
+
+		field.Text("title", "名称"),
+
+		field.Select("type", "类型").
+			SetOptions([]selectfield.Option{
+				field.SelectOption("满减券", model.CouponTypeFixed),
+				field.SelectOption("折扣券", model.CouponTypePercent),
+			}),
+
+		field.Datetime("start_time", "生效时间"),
+
+		field.Datetime("end_time", "过期时间"),
+
+		field.Select("status", "状态").
+			SetOptions(p.statusOptions()),
+
+		field.Number("order_id", "使用订单ID"),
+
+		field.Datetime("used_time", "使用时间").
+			OnlyOnDetail(),
+
+		field.Datetime("created_at", "领取时间"),
+	}
+}
+
+// 搜索
+func (p *UserCoupon) Searches(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		searches.Input("uid", "用户ID"),
+		searches.Input("coupon_id", "优惠券ID"),
+		searches.Select("status", "状态").SetOptions(p.statusOptions()),
+		searches.DatetimeRange("created_at", "领取时间"),
+	}
+}
+
+// 行为
+func (p *UserCoupon) Actions(ctx *quark.Context) []interface{} {
+	return []interface{}{
+		actions.DetailLink(),
+	}
+}
+
+// 状态选项
+func (p *UserCoupon) statusOptions() []selectfield.Option {
+	return []selectfield.Option{
+		{Label: "未使用", Value: model.UserCouponStatusUnused},
+		{Label: "已使用", Value: model.UserCouponStatusUsed},
+	}
+}
@@ -0,0 +1,46 @@
+package home
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Article struct{}
+
+// 文章详情，设置了访问密码的文章通过 password 参数校验
+//
+// 模板依次使用文章的详情模板、分类的详情模板、detail.html
+func (p *Article) Detail(ctx *quark.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		return notFound(ctx)
+	}
+
+	article, err := service.NewPostService().GetArticleDetail(id, ctx.QueryParam("password"))
+	passwordRequired := errors.Is(err, service.ErrPostPasswordRequired) || errors.Is(err, service.ErrPostPasswordWrong)
+	if err != nil && !passwordRequired {
+		return notFound(ctx)
+	}
+
+	data := map[string]interface{}{
+		"article":           article,
+		"password_required": passwordRequired,
+		"error":             "",
+	}
+	if errors.Is(err, service.ErrPostPasswordWrong) {
+		data["error"] = err.Error()
+	}
+
+	category := model.Category{}
+	if article.CategoryId > 0 {
+		category, _ = service.NewCategoryService().GetInfoByKey(strconv.Itoa(article.CategoryId), "ARTICLE")
+	}
+	data["category"] = category
+
+	return render(ctx, data, article.PageTpl, category.DetailTpl, "detail.html")
+}
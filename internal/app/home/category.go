@@ -0,0 +1,94 @@
+package home
+
+import (
+	"strconv"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Category struct{}
+
+// 频道页，展示子分类和分类下的最新文章
+//
+// 模板依次使用分类的频道模板、channel.html、list.html
+func (p *Category) Channel(ctx *quark.Context) error {
+	category, err := service.NewCategoryService().GetInfoByKey(ctx.Param("category"), "ARTICLE")
+	if err != nil {
+		return notFound(ctx)
+	}
+
+	children, err := service.NewCategoryService().GetChildren(category.Id, "ARTICLE")
+	if err != nil {
+		return err
+	}
+
+	articles, total, err := service.NewPostService().GetArticlePage(request.ArticleIndexQueryReq{
+		PageReq:    request.PageReq{Page: 1, PageSize: p.pageNum(category.PageNum)},
+		CategoryId: category.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	return render(ctx, map[string]interface{}{
+		"category": category,
+		"children": children,
+		"articles": articles,
+		"total":    total,
+	}, category.IndexTpl, "channel.html", "list.html")
+}
+
+// 列表页，每页数量使用分类的分页数量
+//
+// 模板依次使用分类的列表模板、list.html
+func (p *Category) List(ctx *quark.Context) error {
+	category, err := service.NewCategoryService().GetInfoByKey(ctx.Param("category"), "ARTICLE")
+	if err != nil {
+		return notFound(ctx)
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	if page <= 0 {
+		page = 1
+	}
+	pageNum := p.pageNum(category.PageNum)
+
+	articles, total, err := service.NewPostService().GetArticlePage(request.ArticleIndexQueryReq{
+		PageReq:    request.PageReq{Page: page, PageSize: pageNum},
+		CategoryId: category.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	totalPages := int((total + int64(pageNum) - 1) / int64(pageNum))
+	prevPage, nextPage := 0, 0
+	if page > 1 {
+		prevPage = page - 1
+	}
+	if page < totalPages {
+		nextPage = page + 1
+	}
+
+	return render(ctx, map[string]interface{}{
+		"category":    category,
+		"articles":    articles,
+		"total":       total,
+		"page":        page,
+		"page_num":    pageNum,
+		"total_pages": totalPages,
+		"prev_page":   prevPage,
+		"next_page":   nextPage,
+	}, category.ListTpl, "list.html")
+}
+
+// 分页数量，未设置时默认为10
+func (p *Category) pageNum(pageNum int) int {
+	if pageNum <= 0 {
+		return 10
+	}
+	return pageNum
+}
@@ -0,0 +1,33 @@
+package home
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Page struct{}
+
+// 单页详情，通过缩略名访问
+//
+// 模板依次使用单页的页面模板、page.html
+func (p *Page) Detail(ctx *quark.Context) error {
+	page, err := service.NewPostService().GetPageDetail(ctx.Param("name"), ctx.QueryParam("password"))
+	passwordRequired := errors.Is(err, service.ErrPostPasswordRequired) || errors.Is(err, service.ErrPostPasswordWrong)
+	if err != nil && !passwordRequired {
+		return notFound(ctx)
+	}
+
+	data := map[string]interface{}{
+		"page":              page,
+		"password_required": passwordRequired,
+		"error":             "",
+	}
+	if errors.Is(err, service.ErrPostPasswordWrong) {
+		data["error"] = err.Error()
+	}
+
+	return render(ctx, data, page.PageTpl, "page.html")
+}
@@ -0,0 +1,25 @@
+package home
+
+import (
+	"net/http"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/template"
+)
+
+// 渲染模板，依次使用第一个存在的模板，为空的模板名会被跳过
+func render(ctx *quark.Context, data map[string]interface{}, names ...string) error {
+	name := names[len(names)-1]
+	if renderer, ok := ctx.Engine.Echo().Renderer.(*template.Template); ok {
+		name = renderer.Pick(names...)
+	}
+	return ctx.Render(http.StatusOK, name, data)
+}
+
+// 页面不存在，未提供 404.html 模板时输出文本
+func notFound(ctx *quark.Context) error {
+	if renderer, ok := ctx.Engine.Echo().Renderer.(*template.Template); ok && renderer.Exists("404.html") {
+		return ctx.Render(http.StatusNotFound, "404.html", map[string]interface{}{})
+	}
+	return ctx.String(http.StatusNotFound, "页面不存在")
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Address struct{}
+
+// 收货地址列表
+func (p *Address) Index(ctx *quark.Context) error {
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	list, err := service.NewAddressService().GetList(uid)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", list)
+}
+
+// 保存收货地址
+func (p *Address) Save(ctx *quark.Context) error {
+	var param request.AddressSaveReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	address, err := service.NewAddressService().Save(uid, param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("保存成功", address)
+}
+
+// 设置默认收货地址
+func (p *Address) SetDefault(ctx *quark.Context) error {
+	var param request.AddressActionReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewAddressService().SetDefault(uid, param.Id); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("设置成功")
+}
+
+// 删除收货地址
+func (p *Address) Delete(ctx *quark.Context) error {
+	var param request.AddressActionReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewAddressService().Delete(uid, param.Id); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("删除成功")
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Article struct{}
+
+// 文章列表
+func (p *Article) Index(ctx *quark.Context) error {
+	param := request.ArticleIndexQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	articles, total, err := service.NewPostService().GetArticlePage(param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	return ctx.JSONOk("ok", response.PageResp{
+		Page:     param.Page,
+		PageSize: param.PageSize,
+		Total:    total,
+		List:     articles,
+	})
+}
+
+// 文章详情
+func (p *Article) Detail(ctx *quark.Context) error {
+	param := request.ArticleDetailQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	article, err := service.NewPostService().GetArticleDetail(param.Id, param.Password)
+	if err != nil {
+		return postError(ctx, err)
+	}
+	return ctx.JSONOk("ok", article)
+}
+
+// 返回文章、单页错误，需要访问密码时附带 password_required 标记
+func postError(ctx *quark.Context, err error) error {
+	if errors.Is(err, service.ErrPostPasswordRequired) || errors.Is(err, service.ErrPostPasswordWrong) {
+		return ctx.JSONError(err.Error(), map[string]interface{}{
+			"password_required": true,
+		})
+	}
+	return ctx.JSONError(err.Error())
+}
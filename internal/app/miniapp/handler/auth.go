@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Auth struct{}
+
+// 刷新令牌
+func (p *Auth) Refresh(ctx *quark.Context) error {
+	param := &request.RefreshTokenReq{}
+	if err := bind(ctx, param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	token, err := service.NewAuthService(ctx).Refresh(param.RefreshToken)
+	if err != nil {
+		return ctx.JSON(401, quark.Error(err.Error()))
+	}
+	return ctx.JSONOk("获取成功", token)
+}
+
+// 退出登录
+func (p *Auth) Logout(ctx *quark.Context) error {
+	if err := service.NewAuthService(ctx).Logout(); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("已退出登录")
+}
+
+// 退出所有设备
+func (p *Auth) LogoutAll(ctx *quark.Context) error {
+	if err := service.NewAuthService(ctx).LogoutAll(); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("已退出所有设备")
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Bill struct{}
+
+// 账单列表
+func (p *Bill) Index(ctx *quark.Context) error {
+	param := request.BillIndexQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	bills, total, err := service.NewBillService().GetPage(uid, param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	return ctx.JSONOk("ok", response.PageResp{
+		Page:     param.Page,
+		PageSize: param.PageSize,
+		Total:    total,
+		List:     bills,
+	})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/validator"
+)
+
+// 绑定请求参数，并根据结构体标签校验
+//
+// ctx.Bind 绑定后会将零值字段设置为 default 标签的值，校验在填充默认值之后进行
+func bind(ctx *quark.Context, param interface{}) error {
+	if err := ctx.Bind(param); err != nil {
+		return errors.New("参数格式错误")
+	}
+	return validator.Validate(param)
+}
+
+// 返回参数错误，校验未通过时在 data.errors 中附带各字段的错误
+func paramError(ctx *quark.Context, err error) error {
+	var errs validator.Errors
+	if errors.As(err, &errs) {
+		return ctx.JSONError(errs.Error(), map[string]interface{}{
+			"errors": errs,
+		})
+	}
+	return ctx.JSONError(err.Error())
+}
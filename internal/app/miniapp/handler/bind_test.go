@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	_ "github.com/quarkcloudio/quark-smart/v2/internal/testenv"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+)
+
+func newJSONContext(body string) *quark.Context {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return &quark.Context{EchoContext: echo.New().NewContext(req, rec), Request: req, Writer: rec}
+}
+
+func TestBindAppliesDefaults(t *testing.T) {
+	for _, body := range []string{`{"order_no":"1"}`, `{"order_no":"1","scene":""}`} {
+		param := request.OrderPayReq{}
+		if err := bind(newJSONContext(body), &param); err != nil {
+			t.Fatal(err)
+		}
+		if param.Scene != pay.SceneApplet {
+			t.Fatalf("expected default scene for %s, got %q", body, param.Scene)
+		}
+	}
+
+	param := request.SubmitOrderReq{}
+	err := bind(newJSONContext(`{"realname":"测试","user_phone":"13800000000","order_details":[{"item_id":1,"pay_num":1}]}`), &param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if param.ShippingType != 1 {
+		t.Fatalf("expected default shipping type, got %d", param.ShippingType)
+	}
+}
+
+func TestBindValidatesAfterDefaults(t *testing.T) {
+	param := request.OrderPayReq{}
+	if err := bind(newJSONContext(`{"order_no":"1","scene":"unknown"}`), &param); err == nil {
+		t.Fatal("expected scene error")
+	}
+
+	submit := request.SubmitOrderReq{}
+	if err := bind(newJSONContext(`{"shipping_type":3,"order_details":[{"item_id":1,"pay_num":1}]}`), &submit); err == nil {
+		t.Fatal("expected shipping type error")
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Cart struct{}
+
+// 购物车列表
+func (p *Cart) Index(ctx *quark.Context) error {
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	list, err := service.NewCartService().GetList(uid)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", list)
+}
+
+// 加入购物车
+func (p *Cart) Add(ctx *quark.Context) error {
+	var param request.CartAddReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewCartService().Add(uid, param); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("加入成功")
+}
+
+// 修改购物车数量
+func (p *Cart) Update(ctx *quark.Context) error {
+	var param request.CartUpdateReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewCartService().Update(uid, param); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("修改成功")
+}
+
+// 删除购物车商品
+func (p *Cart) Delete(ctx *quark.Context) error {
+	var param request.CartDeleteReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewCartService().Delete(uid, param.Ids); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("删除成功")
+}
+
+// 购物车结算
+func (p *Cart) Checkout(ctx *quark.Context) error {
+	var param request.CartCheckoutReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewCartService().Checkout(uid, param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("下单成功", response.SubmitOrderResp{
+		OrderNo: order.OrderNo,
+	})
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Category struct{}
+
+// 分类树
+func (p *Category) Tree(ctx *quark.Context) error {
+	param := request.CategoryTreeQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	categories, err := service.NewCategoryService().GetTree(param.Type)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", categories)
+}
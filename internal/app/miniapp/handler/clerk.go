@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Clerk struct{}
+
+// 核销订单
+func (p *Clerk) Verify(ctx *quark.Context) error {
+	var param request.ClerkVerifyReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	clerk, err := service.NewClerkService().GetInfoByUid(uid)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	order, err := service.NewClerkService().Verify(clerk, param.Code)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("核销成功", response.ClerkVerifyResp{
+		OrderNo:  order.OrderNo,
+		TotalNum: order.TotalNum,
+		PayPrice: order.PayPrice,
+	})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Coupon struct{}
+
+// 可领取的优惠券列表
+func (p *Coupon) Index(ctx *quark.Context) error {
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	list, err := service.NewCouponService().GetClaimableList(uid)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", list)
+}
+
+// 领取优惠券
+func (p *Coupon) Receive(ctx *quark.Context) error {
+	var param request.CouponReceiveReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	userCoupon, err := service.NewCouponService().Receive(uid, param.CouponId)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("领取成功", userCoupon)
+}
+
+// 我的优惠券
+func (p *Coupon) My(ctx *quark.Context) error {
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	list, err := service.NewCouponService().GetUserList(uid, ctx.QueryParam("status"))
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", list)
+}
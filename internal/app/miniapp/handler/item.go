@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+// 结构体
+type Item struct{}
+
+// 商品列表
+func (p *Item) Index(ctx *quark.Context) error {
+	param := request.ItemIndexQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	items, total, err := service.NewItemService().GetPage(param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	for index, item := range items {
+		// 处理图片 url
+		items[index].Image = utils.GetImagePath(item.Image)
+	}
+
+	return ctx.JSONOk("ok", response.PageResp{
+		Page:     param.Page,
+		PageSize: param.PageSize,
+		Total:    total,
+		List:     items,
+	})
+}
+
+// 商品详情
+func (p *Item) Detail(ctx *quark.Context) error {
+	id, err := strconv.Atoi(ctx.QueryParam("id"))
+	if err != nil || id <= 0 {
+		return ctx.JSONError("参数错误")
+	}
+	item, err := service.NewItemService().GetDetail(id)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", item)
+}
+
+// 商品分类
+func (p *Item) Categories(ctx *quark.Context) error {
+	categories, err := service.NewItemService().GetCategoryTree()
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", categories)
+}
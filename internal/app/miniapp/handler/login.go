@@ -3,7 +3,9 @@ package handler
 import (
 	"github.com/dchest/captcha"
 	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
 	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
 	"github.com/quarkcloudio/quark-smart/v2/internal/service"
 )
 
@@ -13,11 +15,8 @@ type Login struct{}
 // 用户名、密码登录
 func (p *Login) Index(ctx *quark.Context) error {
 	loginReq := &request.LoginReq{}
-	if err := ctx.Bind(loginReq); err != nil {
-		return ctx.JSONError(err.Error())
-	}
-	if loginReq.Captcha.Id == "" || loginReq.Captcha.Value == "" {
-		return ctx.JSONError("验证码不能为空")
+	if err := bind(ctx, loginReq); err != nil {
+		return paramError(ctx, err)
 	}
 
 	verifyResult := captcha.VerifyString(loginReq.Captcha.Id, loginReq.Captcha.Value)
@@ -26,17 +25,56 @@ func (p *Login) Index(ctx *quark.Context) error {
 	}
 	captcha.Reload(loginReq.Captcha.Id)
 
-	if loginReq.Username == "" || loginReq.Password == "" {
-		return ctx.JSONError("用户名或密码不能为空")
-	}
 	token, err := service.NewAuthService(ctx).Login(loginReq.Username, loginReq.Password)
 	if err != nil {
 		return ctx.JSONError(err.Error())
 	}
 
-	return ctx.JSONOk("获取成功", map[string]interface{}{
-		"token": token,
+	return ctx.JSONOk("获取成功", token)
+}
+
+// 手机号验证码登录
+func (p *Login) Phone(ctx *quark.Context) error {
+	phoneLoginReq := &request.PhoneLoginReq{}
+	if err := bind(ctx, phoneLoginReq); err != nil {
+		return paramError(ctx, err)
+	}
+
+	token, err := service.NewAuthService(ctx).PhoneLogin(phoneLoginReq.Phone, phoneLoginReq.Code)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	return ctx.JSONOk("获取成功", token)
+}
+
+// 微信小程序登录
+func (p *Login) WechatMP(ctx *quark.Context) error {
+	return p.wechat(ctx, model.WechatPlatformMP)
+}
+
+// 微信公众号网页授权登录
+func (p *Login) WechatOA(ctx *quark.Context) error {
+	return p.wechat(ctx, model.WechatPlatformOA)
+}
+
+// 微信授权登录
+func (p *Login) wechat(ctx *quark.Context, platform string) error {
+	param := &request.WechatLoginReq{}
+	if err := bind(ctx, param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	token, err := service.NewAuthService(ctx).WechatLogin(platform, dto.WechatAuthDTO{
+		Code:          param.Code,
+		Iv:            param.Iv,
+		EncryptedData: param.EncryptedData,
 	})
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	return ctx.JSONOk("获取成功", token)
 }
 
 // 模拟登录
@@ -45,7 +83,5 @@ func (p *Login) Mock(ctx *quark.Context) error {
 	if err != nil {
 		return ctx.JSONError(err.Error())
 	}
-	return ctx.JSONOk("获取成功", map[string]interface{}{
-		"token": token,
-	})
+	return ctx.JSONOk("获取成功", token)
 }
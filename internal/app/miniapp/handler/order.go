@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+)
+
+// 结构体
+type Order struct{}
+
+// 提交订单
+func (p *Order) Submit(ctx *quark.Context) error {
+	var param request.SubmitOrderReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewOrderService().Submit(uid, param)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("下单成功", response.SubmitOrderResp{
+		OrderNo: order.OrderNo,
+	})
+}
+
+// 订单详情
+func (p *Order) Detail(ctx *quark.Context) error {
+	orderNo := ctx.QueryParam("order_no")
+	if orderNo == "" {
+		return ctx.JSONError("参数错误")
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewOrderService().GetUserOrderDetail(uid, orderNo)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", order)
+}
+
+// 取消订单
+func (p *Order) Cancel(ctx *quark.Context) error {
+	return p.transition(ctx, model.OrderEventCancel, "取消成功")
+}
+
+// 确认收货
+func (p *Order) Receive(ctx *quark.Context) error {
+	return p.transition(ctx, model.OrderEventReceive, "收货成功")
+}
+
+// 申请退款
+func (p *Order) Refund(ctx *quark.Context) error {
+	var param request.OrderRefundReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if _, err := service.NewRefundService().Apply(uid, param); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("申请成功，请等待审核")
+}
+
+// 订单核销码
+func (p *Order) VerifyCode(ctx *quark.Context) error {
+	orderNo := ctx.QueryParam("order_no")
+	if orderNo == "" {
+		return ctx.JSONError("参数错误")
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewOrderService().GetUserOrderByOrderNo(uid, orderNo)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	if order.ShippingType != model.ShippingTypePickup || order.VerifyCode == "" {
+		return ctx.JSONError("订单没有核销码")
+	}
+
+	return ctx.JSONOk("ok", response.OrderVerifyCodeResp{
+		VerifyCode: order.VerifyCode,
+		Qrcode:     service.NewClerkService().GetVerifyQrcode(order.VerifyCode),
+	})
+}
+
+// 订单物流
+func (p *Order) Express(ctx *quark.Context) error {
+	orderNo := ctx.QueryParam("order_no")
+	if orderNo == "" {
+		return ctx.JSONError("参数错误")
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewOrderService().GetUserOrderByOrderNo(uid, orderNo)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	result, err := service.NewLogisticsService().TrackOrder(order)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	traces := []response.OrderExpressTraceResp{}
+	for _, trace := range result.Traces {
+		traces = append(traces, response.OrderExpressTraceResp{
+			Time:    trace.Time.Format(time.DateTime),
+			Content: trace.Content,
+		})
+	}
+	return ctx.JSONOk("ok", response.OrderExpressResp{
+		DeliveryName: order.DeliveryName,
+		DeliveryNo:   order.DeliveryNo,
+		State:        result.State,
+		Traces:       traces,
+	})
+}
+
+// 用户操作订单状态
+func (p *Order) transition(ctx *quark.Context, event model.OrderEvent, message string) error {
+	var param request.OrderActionReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	order, err := service.NewOrderService().GetUserOrderByOrderNo(uid, param.OrderNo)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	_, err = service.NewOrderService().Transition(dto.OrderTransitionDTO{
+		OrderId:   order.Id,
+		Event:     event,
+		ActorType: model.OrderActorUser,
+		ActorId:   uid,
+		Reason:    param.Reason,
+	})
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk(message)
+}
+
+// 订单支付
+func (p *Order) Pay(ctx *quark.Context) error {
+	var param request.OrderPayReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	user, err := service.NewAuthService(ctx).GetUser()
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	order, err := service.NewOrderService().GetUserOrderByOrderNo(user.Id, param.OrderNo)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	// 未指定支付场景时默认为小程序支付
+	if param.Scene == "" {
+		param.Scene = pay.SceneApplet
+	}
+
+	// 公众号支付使用公众号 openid，其他场景使用小程序 openid
+	platform := model.WechatPlatformMP
+	if param.Scene == pay.SceneJSAPI {
+		platform = model.WechatPlatformOA
+	}
+	openId := service.NewUserWechatService().GetOpenid(user.Id, platform)
+
+	result, err := service.NewPayService().CreatePayment(order, param.Scene, openId, ctx.ClientIP())
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("ok", response.OrderPayResp{
+		Scene:  result.Scene,
+		Params: result.Params,
+		Url:    result.Url,
+	})
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Page struct{}
+
+// 单页详情
+func (p *Page) Detail(ctx *quark.Context) error {
+	param := request.PageDetailQueryReq{}
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	page, err := service.NewPostService().GetPageDetail(param.Name, param.Password)
+	if err != nil {
+		return postError(ctx, err)
+	}
+	return ctx.JSONOk("ok", page)
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
 )
 
 // 结构体
@@ -9,5 +11,15 @@ type Register struct{}
 
 // 用户注册
 func (p *Register) Index(ctx *quark.Context) error {
-	return ctx.JSONOk("Hello, world!")
+	registerReq := &request.RegisterReq{}
+	if err := bind(ctx, registerReq); err != nil {
+		return paramError(ctx, err)
+	}
+
+	token, err := service.NewAuthService(ctx).Register(registerReq.Username, registerReq.Password, registerReq.Phone, registerReq.Code)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	return ctx.JSONOk("获取成功", token)
 }
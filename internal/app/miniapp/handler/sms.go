@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Sms struct{}
+
+// 发送短信验证码
+func (p *Sms) Send(ctx *quark.Context) error {
+	var param request.SmsSendReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	err := service.NewSmsCodeService().Send(ctx.Request.Context(), param.Phone, param.Scene, ctx.ClientIP())
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("发送成功")
+}
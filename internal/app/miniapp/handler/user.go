@@ -16,6 +16,9 @@ type User struct{}
 func (p *User) Index(ctx *quark.Context) error {
 	uid, _ := service.NewAuthService(ctx).GetUid()
 	user, _ := service.NewUserService().GetInfoById(uid)
+	if service.NewUserService().IsPlaceholder(user.Phone) {
+		user.Phone = ""
+	}
 	userInfo := response.UserInfoResp{
 		Id:       user.Id,
 		Nickname: user.Nickname,
@@ -28,20 +31,22 @@ func (p *User) Index(ctx *quark.Context) error {
 // 更新用户信息
 func (p *User) Save(ctx *quark.Context) error {
 	var param request.UpdateUserReq
-	if err := ctx.Bind(&param); err != nil {
-		return ctx.JSONError(err.Error())
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
 	}
 
-	// 参数校验
-	if param.Phone != "" && !utils.CheckRegex("^(13[0-9]|14[01456879]|15[0-35-9]|16[2567]|17[0-8]|18[0-9]|19[0-35-9])\\d{8}$", param.Phone) {
-		return ctx.JSONError("手机号格式不正确")
+	uid, _ := service.NewAuthService(ctx).GetUid()
+
+	// 手机号需通过短信验证码绑定，不随用户信息直接更新
+	if param.Phone != "" {
+		if err := service.NewUserService().BindPhone(uid, param.Phone, param.Code); err != nil {
+			return ctx.JSONError(err.Error())
+		}
 	}
 
-	uid, _ := service.NewAuthService(ctx).GetUid()
 	if _, err := service.NewUserService().UpdateUser(dto.SaveUserDTO{
 		Id:       uid,
 		Nickname: param.Nickname,
-		Phone:    param.Phone,
 		Avatar:   param.Avatar,
 	}); err != nil {
 		return ctx.JSONError("更新用户信息失败")
@@ -49,11 +54,64 @@ func (p *User) Save(ctx *quark.Context) error {
 	return ctx.JSONOk("更新成功")
 }
 
-// 注销用户信息
-func (p *User) Delete(ctx *quark.Context) error {
+// 通过微信手机号快速验证组件绑定手机号
+func (p *User) Phone(ctx *quark.Context) error {
+	var param request.UserPhoneReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
 	uid, _ := service.NewAuthService(ctx).GetUid()
-	if err := service.NewUserService().DeleteUser(uid); err != nil {
-		return ctx.JSONError("注销失败")
+	phone, err := service.NewUserService().BindWechatPhone(uid, param.Code)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("绑定成功", map[string]interface{}{
+		"phone": phone,
+	})
+}
+
+// 修改密码，修改后其他设备需重新登录
+func (p *User) Password(ctx *quark.Context) error {
+	var param request.UserPasswordReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	token, err := service.NewAuthService(ctx).ChangePassword(param.OldPassword, param.Password)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("修改成功", token)
+}
+
+// 申请注销账号，冷静期结束后注销，冷静期内重新登录即撤销
+func (p *User) Delete(ctx *quark.Context) error {
+	var param request.UserDeleteReq
+	if err := bind(ctx, &param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	user, err := service.NewAuthService(ctx).GetUser()
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	// 绑定了手机号的用户需验证短信验证码
+	if !service.NewUserService().IsPlaceholder(user.Phone) {
+		if param.Code == "" {
+			return ctx.JSONError("验证码不能为空")
+		}
+		if err := service.NewSmsCodeService().Verify(user.Phone, service.SmsSceneDelete, param.Code); err != nil {
+			return ctx.JSONError(err.Error())
+		}
+	}
+
+	deletion, err := service.NewUserDeletionService().Apply(user.Id, param.Reason)
+	if err != nil {
+		return ctx.JSONError(err.Error())
 	}
-	return ctx.JSONOk("注销成功")
+	return ctx.JSONOk("已申请注销，冷静期内重新登录即可撤销", map[string]interface{}{
+		"scheduled_at": deletion.ScheduledAt,
+	})
 }
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 结构体
+type Wechat struct{}
+
+// 已绑定的微信
+func (p *Wechat) Index(ctx *quark.Context) error {
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	list, err := service.NewUserWechatService().GetListByUid(uid)
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+
+	platforms := []map[string]interface{}{}
+	for _, v := range list {
+		platforms = append(platforms, map[string]interface{}{
+			"platform":   v.Platform,
+			"nickname":   v.Nickname,
+			"avatar":     v.Avatar,
+			"created_at": v.CreatedAt,
+		})
+	}
+	return ctx.JSONOk("ok", platforms)
+}
+
+// 绑定微信
+func (p *Wechat) Bind(ctx *quark.Context) error {
+	param := &request.WechatBindReq{}
+	if err := bind(ctx, param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	err := service.NewAuthService(ctx).WechatBind(param.Platform, dto.WechatAuthDTO{
+		Code:          param.Code,
+		Iv:            param.Iv,
+		EncryptedData: param.EncryptedData,
+	})
+	if err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("绑定成功")
+}
+
+// 解绑微信
+func (p *Wechat) Unbind(ctx *quark.Context) error {
+	param := &request.WechatUnbindReq{}
+	if err := bind(ctx, param); err != nil {
+		return paramError(ctx, err)
+	}
+
+	uid, _ := service.NewAuthService(ctx).GetUid()
+	if err := service.NewUserWechatService().Unbind(uid, param.Platform); err != nil {
+		return ctx.JSONError(err.Error())
+	}
+	return ctx.JSONOk("解绑成功")
+}
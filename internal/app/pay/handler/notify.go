@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+)
+
+// 结构体
+type Notify struct{}
+
+// 微信支付异步通知
+func (p *Notify) Wechat(ctx *quark.Context) error {
+	gateway, err := service.NewPayService().GetGateway(pay.ChannelWechat)
+	if err != nil {
+		return p.wechatFail(ctx, err.Error())
+	}
+
+	notification, err := gateway.ParseNotification(ctx.Request)
+	if err != nil {
+		return p.wechatFail(ctx, err.Error())
+	}
+	if err = service.NewPayService().HandleNotification(ctx.Request.Context(), notification); err != nil {
+		return p.wechatFail(ctx, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{
+		"code":    "SUCCESS",
+		"message": "成功",
+	})
+}
+
+// 微信支付通知处理失败，微信会按策略重新发送通知
+func (p *Notify) wechatFail(ctx *quark.Context, message string) error {
+	return ctx.JSON(http.StatusInternalServerError, map[string]string{
+		"code":    "FAIL",
+		"message": message,
+	})
+}
+
+// 支付宝异步通知
+func (p *Notify) Alipay(ctx *quark.Context) error {
+	gateway, err := service.NewPayService().GetGateway(pay.ChannelAlipay)
+	if err != nil {
+		return ctx.String(http.StatusOK, "fail")
+	}
+
+	notification, err := gateway.ParseNotification(ctx.Request)
+	if err != nil {
+		return ctx.String(http.StatusOK, "fail")
+	}
+	if err = service.NewPayService().HandleNotification(ctx.Request.Context(), notification); err != nil {
+		return ctx.String(http.StatusOK, "fail")
+	}
+
+	// 支付宝需要返回 success 字符串，否则会重新发送通知
+	return ctx.String(http.StatusOK, "success")
+}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/labstack/echo/v4"
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	_ "github.com/quarkcloudio/quark-smart/v2/internal/testenv"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay/paytest"
+	"gorm.io/gorm"
+)
+
+const testApiV3Key = "0123456789abcdef0123456789abcdef"
+
+// 测试支付网关，使用本地签名的通知，退款直接成功
+type testGateway struct {
+	*pay.FakeGateway
+	channel string
+	parse   func(req *http.Request) (*pay.Notification, error)
+	refunds []pay.RefundRequest
+}
+
+func (p *testGateway) Channel() string {
+	return p.channel
+}
+
+func (p *testGateway) Refund(ctx context.Context, req pay.RefundRequest) (*pay.RefundResult, error) {
+	p.refunds = append(p.refunds, req)
+	return &pay.RefundResult{
+		OrderNo:  req.OrderNo,
+		RefundNo: req.RefundNo,
+		RefundId: "test" + req.RefundNo,
+		Amount:   req.Amount,
+		Status:   pay.RefundStatusSuccess,
+	}, nil
+}
+
+func (p *testGateway) ParseNotification(req *http.Request) (*pay.Notification, error) {
+	return p.parse(req)
+}
+
+type testEnv struct {
+	wechat        *paytest.WechatNotifier
+	alipay        *paytest.AlipayNotifier
+	wechatGateway *testGateway
+	alipayGateway *testGateway
+}
+
+// 使用内存数据库和本地签名的支付网关
+func setup(t *testing.T) *testEnv {
+	t.Helper()
+
+	client, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := client.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	err = client.AutoMigrate(
+		&appmodel.Config{},
+		&model.Order{},
+		&model.OrderDetail{},
+		&model.OrderLog{},
+		&model.OrderRefund{},
+		&model.Bill{},
+		&model.UserBalance{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Client = client
+
+	wechatNotifier, err := paytest.NewWechatNotifier(testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alipayNotifier, err := paytest.NewAlipayNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := &testEnv{
+		wechat: wechatNotifier,
+		alipay: alipayNotifier,
+		wechatGateway: &testGateway{
+			FakeGateway: pay.NewFakeGateway(),
+			channel:     pay.ChannelWechat,
+			parse: func(req *http.Request) (*pay.Notification, error) {
+				return pay.ParseWechatNotify(req, testApiV3Key, wechatNotifier.PublicKeys())
+			},
+		},
+		alipayGateway: &testGateway{
+			FakeGateway: pay.NewFakeGateway(),
+			channel:     pay.ChannelAlipay,
+			parse: func(req *http.Request) (*pay.Notification, error) {
+				return pay.ParseAliPayNotify(req, alipayNotifier.PublicCert)
+			},
+		},
+	}
+	pay.RegisterGateway(pay.ChannelWechat, func() (pay.Gateway, error) { return env.wechatGateway, nil })
+	pay.RegisterGateway(pay.ChannelAlipay, func() (pay.Gateway, error) { return env.alipayGateway, nil })
+
+	return env
+}
+
+func createOrder(t *testing.T, order model.Order) model.Order {
+	t.Helper()
+	order.Uid = 1
+	order.Realname = "测试"
+	order.UserPhone = "13800000000"
+	order.UserAddress = "测试地址"
+	if err := db.Client.Create(&order).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func getOrder(t *testing.T, id int) model.Order {
+	t.Helper()
+	order := model.Order{}
+	if err := db.Client.Where("id = ?", id).First(&order).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func getBills(t *testing.T, uid int) []model.Bill {
+	t.Helper()
+	bills := []model.Bill{}
+	if err := db.Client.Where("uid = ?", uid).Order("id asc").Find(&bills).Error; err != nil {
+		t.Fatal(err)
+	}
+	return bills
+}
+
+func serve(t *testing.T, handle func(ctx *quark.Context) error, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	ctx := &quark.Context{EchoContext: echo.New().NewContext(req, rec), Request: req, Writer: rec}
+	if err := handle(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func wechatPayment(t *testing.T, env *testEnv, orderNo string, total int) *http.Request {
+	t.Helper()
+	req, err := env.wechat.Request("TRANSACTION.SUCCESS", map[string]interface{}{
+		"out_trade_no":   orderNo,
+		"transaction_id": "4200" + orderNo,
+		"trade_state":    "SUCCESS",
+		"amount":         map[string]interface{}{"total": total, "payer_total": total},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func wechatRefund(t *testing.T, env *testEnv, orderNo string, refundNo string, refund int) *http.Request {
+	t.Helper()
+	req, err := env.wechat.Request("REFUND.SUCCESS", map[string]interface{}{
+		"out_trade_no":   orderNo,
+		"transaction_id": "4200" + orderNo,
+		"out_refund_no":  refundNo,
+		"refund_id":      "5000" + refundNo,
+		"refund_status":  "SUCCESS",
+		"amount":         map[string]interface{}{"refund": refund},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func assertWechatCode(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	body := map[string]string{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != status || body["code"] != code {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWechatNotifyPaid(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "W001", PayPrice: 19.99, Status: model.OrderStatusPendingPayment})
+
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatPayment(t, env, "W001", 1999)), http.StatusOK, "SUCCESS")
+
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusPaid || order.Paid != 1 || order.PayType != pay.ChannelWechat || order.TransactionId != "4200W001" {
+		t.Fatalf("order not paid: %+v", order)
+	}
+
+	// 重复通知直接返回成功，不重复流转
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatPayment(t, env, "W001", 1999)), http.StatusOK, "SUCCESS")
+	var count int64
+	db.Client.Model(&model.OrderLog{}).Where("order_id = ?", order.Id).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 order log, got %d", count)
+	}
+
+	// 在线支付记录账单，不扣减余额
+	bills := getBills(t, order.Uid)
+	if len(bills) != 1 || bills[0].Type != model.BillTypePayOrder || bills[0].PM != model.BillPMExpense || bills[0].Number != 19.99 || bills[0].LinkId != "W001" {
+		t.Fatalf("unexpected bills: %+v", bills)
+	}
+	balance := model.UserBalance{}
+	db.Client.Where("uid = ?", order.Uid).First(&balance)
+	if balance.Balance != 0 {
+		t.Fatalf("balance should not change: %+v", balance)
+	}
+}
+
+func TestWechatNotifyRejectsInvalidSignature(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "W002", PayPrice: 19.99, Status: model.OrderStatusPendingPayment})
+
+	other, err := paytest.NewWechatNotifier(testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.SerialNo = env.wechat.SerialNo
+	req, err := other.Request("TRANSACTION.SUCCESS", map[string]interface{}{
+		"out_trade_no": "W002",
+		"trade_state":  "SUCCESS",
+		"amount":       map[string]interface{}{"total": 1999},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, req), http.StatusInternalServerError, "FAIL")
+	if order = getOrder(t, order.Id); order.Paid != 0 {
+		t.Fatalf("order should not be paid: %+v", order)
+	}
+}
+
+func TestWechatNotifyRejectsAmountMismatch(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "W003", PayPrice: 19.99, Status: model.OrderStatusPendingPayment})
+
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatPayment(t, env, "W003", 1)), http.StatusInternalServerError, "FAIL")
+	if order = getOrder(t, order.Id); order.Paid != 0 {
+		t.Fatalf("order should not be paid: %+v", order)
+	}
+}
+
+func TestWechatNotifyPaidAfterCancelRefunds(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "W004", PayPrice: 19.99, Status: model.OrderStatusCancelled})
+
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatPayment(t, env, "W004", 1999)), http.StatusOK, "SUCCESS")
+
+	if len(env.wechatGateway.refunds) != 1 || env.wechatGateway.refunds[0].Amount != 19.99 {
+		t.Fatalf("expected one full refund, got %+v", env.wechatGateway.refunds)
+	}
+	refund := model.OrderRefund{}
+	if err := db.Client.Where("order_id = ?", order.Id).First(&refund).Error; err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != model.OrderRefundStatusSuccess {
+		t.Fatalf("refund not completed: %+v", refund)
+	}
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusCancelled || order.Paid != 1 || order.RefundedPrice != 19.99 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+
+	// 重复通知不重复退款
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatPayment(t, env, "W004", 1999)), http.StatusOK, "SUCCESS")
+	if len(env.wechatGateway.refunds) != 1 {
+		t.Fatalf("expected one refund, got %d", len(env.wechatGateway.refunds))
+	}
+
+	// 支付和退款各记录一条账单
+	bills := getBills(t, order.Uid)
+	if len(bills) != 2 || bills[0].Type != model.BillTypePayOrder || bills[1].Type != model.BillTypePayRefund || bills[1].Number != 19.99 {
+		t.Fatalf("unexpected bills: %+v", bills)
+	}
+}
+
+func TestWechatNotifyRefund(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "W005", PayPrice: 19.99, Paid: 1, PayType: pay.ChannelWechat, Status: model.OrderStatusPaid, RefundStatus: model.RefundStatusApplying})
+	db.Client.Create(&model.OrderRefund{OrderId: order.Id, RefundNo: "W005R1", Amount: 5, ActorType: model.OrderActorAdmin, Status: model.OrderRefundStatusProcessing})
+
+	// 部分退款不改变订单状态
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatRefund(t, env, "W005", "W005R1", 500)), http.StatusOK, "SUCCESS")
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusPaid || order.RefundStatus != model.RefundStatusPartial || order.RefundedPrice != 5 {
+		t.Fatalf("unexpected order after partial refund: %+v", order)
+	}
+
+	// 重复通知不重复累计
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatRefund(t, env, "W005", "W005R1", 500)), http.StatusOK, "SUCCESS")
+	if order = getOrder(t, order.Id); order.RefundedPrice != 5 {
+		t.Fatalf("refund counted twice: %+v", order)
+	}
+
+	// 支付平台后台发起的退款补录退款单，累计退款达到实付金额后订单已退款
+	assertWechatCode(t, serve(t, (&Notify{}).Wechat, wechatRefund(t, env, "W005", "W005R2", 1499)), http.StatusOK, "SUCCESS")
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusRefunded || order.RefundStatus != model.RefundStatusRefunded || order.RefundedPrice != 19.99 {
+		t.Fatalf("unexpected order after full refund: %+v", order)
+	}
+
+	// 每个退款单记录一条退款账单
+	bills := getBills(t, order.Uid)
+	if len(bills) != 2 || bills[0].LinkId != "W005R1" || bills[0].Number != 5 || bills[1].LinkId != "W005R2" || bills[1].Number != 14.99 {
+		t.Fatalf("unexpected bills: %+v", bills)
+	}
+}
+
+func TestAlipayNotifyPaid(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "A001", PayPrice: 19.99, Status: model.OrderStatusPendingPayment})
+
+	req, err := env.alipay.Request(map[string]string{
+		"notify_type":  "trade_status_sync",
+		"out_trade_no": "A001",
+		"trade_no":     "2024A001",
+		"trade_status": "TRADE_SUCCESS",
+		"total_amount": "19.99",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(t, (&Notify{}).Alipay, req)
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "success" {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusPaid || order.PayType != pay.ChannelAlipay || order.TransactionId != "2024A001" {
+		t.Fatalf("order not paid: %+v", order)
+	}
+}
+
+func TestAlipayNotifyRejectsInvalidSignature(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "A002", PayPrice: 19.99, Status: model.OrderStatusPendingPayment})
+
+	other, err := paytest.NewAlipayNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := other.Request(map[string]string{
+		"out_trade_no": "A002",
+		"trade_status": "TRADE_SUCCESS",
+		"total_amount": "19.99",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(t, (&Notify{}).Alipay, req)
+	if strings.TrimSpace(rec.Body.String()) != "fail" {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if order = getOrder(t, order.Id); order.Paid != 0 || env.alipayGateway.refunds != nil {
+		t.Fatalf("order should not be paid: %+v", order)
+	}
+}
+
+func TestAlipayNotifyRefundTotal(t *testing.T) {
+	env := setup(t)
+	order := createOrder(t, model.Order{OrderNo: "A003", PayPrice: 19.99, Paid: 1, PayType: pay.ChannelAlipay, Status: model.OrderStatusPaid})
+
+	refund := func(refundFee string) {
+		// 支付宝后台发起的退款没有 out_biz_no，refund_fee 为累计退款金额
+		req, err := env.alipay.Request(map[string]string{
+			"notify_type":  "trade_status_sync",
+			"out_trade_no": "A003",
+			"trade_no":     "2024A003",
+			"trade_status": "TRADE_SUCCESS",
+			"total_amount": "19.99",
+			"refund_fee":   refundFee,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := serve(t, (&Notify{}).Alipay, req)
+		if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "success" {
+			t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	refund("5.00")
+	refund("8.00")
+	// 重复通知不重复累计
+	refund("8.00")
+
+	order = getOrder(t, order.Id)
+	if order.Status != model.OrderStatusPaid || order.RefundStatus != model.RefundStatusPartial || order.RefundedPrice != 8 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+
+	refunds := []model.OrderRefund{}
+	db.Client.Where("order_id = ?", order.Id).Order("id asc").Find(&refunds)
+	if len(refunds) != 2 || refunds[0].RefundNo != "A003R1" || refunds[0].Amount != 5 || refunds[1].RefundNo != "A003R2" || refunds[1].Amount != 3 {
+		t.Fatalf("unexpected refunds: %+v", refunds)
+	}
+}
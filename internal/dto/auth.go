@@ -6,3 +6,13 @@ type WechatAuthDTO struct {
 	Iv            string // 小程序授权所需的参数，由前端传递
 	EncryptedData string // 小程序授权所需的参数，由前端传递
 }
+
+// 微信身份
+type WechatIdentityDTO struct {
+	Platform string // 微信平台
+	Openid   string // 当前平台的 openid
+	Unionid  string // 开放平台 unionid，未绑定开放平台时为空
+	Nickname string // 昵称
+	Avatar   string // 头像
+	Sex      int    // 性别
+}
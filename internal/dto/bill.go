@@ -0,0 +1,13 @@
+package dto
+
+// 用户记账
+type BillPostDTO struct {
+	Uid      int     // 用户id
+	PM       uint8   // 收支类型
+	Title    string  // 账单标题
+	Category string  // 账单类别，默认为余额，非余额类别不变动余额
+	Type     string  // 明细类型
+	Number   float64 // 金额
+	LinkId   string  // 关联id，同一用户同一类型的关联id只记账一次
+	Mark     string  // 备注
+}
@@ -8,6 +8,7 @@ type ItemDTO struct {
 	Name        string         `json:"name"`         // 商品名称
 	Keyword     string         `json:"keyword"`      // 关键字
 	Description string         `json:"description"`  // 商品简介
+	Content     string         `json:"content"`      // 商品详情
 	CategoryIds string         `json:"category_ids"` // 分类ids
 	Price       float64        `json:"price"`        // 商品价格
 	OtPrice     float64        `json:"ot_price"`     // 市场价
@@ -32,7 +33,7 @@ type ItemAttrItemDTO struct {
 // 管理后台解析用
 type ItemAttrDTO struct {
 	Name  string            `json:"name"` // 属性名
-	Items []ItemAttrItemDTO `json:"itemAttrItem"`
+	Items []ItemAttrItemDTO `json:"items"`
 }
 
 // 商品属性值表
@@ -0,0 +1,10 @@
+package dto
+
+// 对账差异
+type ReconcileDiffDTO struct {
+	Type         string  `json:"type"`          // 记录类型，payment 或 refund
+	OrderNo      string  `json:"order_no"`      // 商户订单号
+	Reason       string  `json:"reason"`        // 差异原因
+	LocalAmount  float64 `json:"local_amount"`  // 本地金额
+	RemoteAmount float64 `json:"remote_amount"` // 支付平台金额
+}
@@ -0,0 +1,6 @@
+package dto
+
+// 队列任务类型
+const (
+	TaskWechatTemplateMessage = "wechat:template_message" // 发送微信模板消息
+)
@@ -0,0 +1,18 @@
+package request
+
+// 保存收货地址
+type AddressSaveReq struct {
+	Id        int    `json:"id" validate:"min=0" label:"地址"` // 地址id，为0时新增
+	Realname  string `json:"realname" validate:"required,max=32" label:"收货人"`
+	Phone     string `json:"phone" validate:"required,regex=phone" label:"手机号"`
+	Province  string `json:"province" validate:"required,max=64" label:"省份"`
+	City      string `json:"city" validate:"required,max=64" label:"城市"`
+	District  string `json:"district" validate:"max=64" label:"区县"`
+	Detail    string `json:"detail" validate:"required,max=200" label:"详细地址"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// 收货地址操作
+type AddressActionReq struct {
+	Id int `json:"id" validate:"required,min=1" label:"地址"`
+}
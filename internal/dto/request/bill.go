@@ -0,0 +1,7 @@
+package request
+
+// 账单列表查询
+type BillIndexQueryReq struct {
+	PageReq
+	PM string `query:"pm" validate:"oneof=0 1" label:"收支类型"` // 收支类型：0支出，1收入，为空时查询全部
+}
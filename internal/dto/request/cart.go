@@ -0,0 +1,30 @@
+package request
+
+// 加入购物车
+type CartAddReq struct {
+	ItemId      int `json:"item_id" validate:"required,min=1" label:"商品"`
+	AttrValueId int `json:"attr_value_id" validate:"min=0" label:"商品规格"`
+	Num         int `json:"num" validate:"required,min=1,max=999" label:"数量"`
+}
+
+// 修改购物车数量
+type CartUpdateReq struct {
+	Id  int `json:"id" validate:"required,min=1" label:"购物车商品"`
+	Num int `json:"num" validate:"required,min=1,max=999" label:"数量"`
+}
+
+// 删除购物车
+type CartDeleteReq struct {
+	Ids []int `json:"ids" validate:"required" label:"购物车商品"`
+}
+
+// 购物车结算
+type CartCheckoutReq struct {
+	Ids          []int  `json:"ids" validate:"required" label:"购物车商品"`
+	ShippingType uint8  `json:"shipping_type" default:"1" validate:"oneof=1 2" label:"配送方式"` // 配送方式：1快递配送，2门店自提
+	AddressId    int    `json:"address_id" validate:"min=0" label:"收货地址"`
+	CouponId     int    `json:"coupon_id" validate:"min=0" label:"优惠券"`
+	Realname     string `json:"realname" validate:"max=32" label:"收货人"`
+	UserPhone    string `json:"user_phone" validate:"regex=phone" label:"手机号"`
+	UserAddress  string `json:"user_address" validate:"max=500" label:"收货地址"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// 领取优惠券
+type CouponReceiveReq struct {
+	CouponId int `json:"coupon_id" validate:"required,min=1" label:"优惠券"`
+}
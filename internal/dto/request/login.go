@@ -2,13 +2,31 @@ package request
 
 // 验证码
 type Captcha struct {
-	Id    string `json:"id"`
-	Value string `json:"value"`
+	Id    string `json:"id" validate:"required" label:"验证码"`
+	Value string `json:"value" validate:"required" label:"验证码"`
 }
 
 // 登录
 type LoginReq struct {
-	Username string  `json:"username"`
-	Password string  `json:"password"`
+	Username string  `json:"username" validate:"required" label:"用户名"`
+	Password string  `json:"password" validate:"required" label:"密码"`
 	Captcha  Captcha `json:"captcha"`
 }
+
+// 手机号验证码登录
+type PhoneLoginReq struct {
+	Phone string `json:"phone" validate:"required,regex=phone" label:"手机号"`
+	Code  string `json:"code" validate:"required,len=6" label:"验证码"`
+}
+
+// 微信授权登录
+type WechatLoginReq struct {
+	Code          string `json:"code" validate:"required" label:"授权参数"`
+	Iv            string `json:"iv"`
+	EncryptedData string `json:"encrypted_data"`
+}
+
+// 刷新令牌
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required" label:"刷新令牌"`
+}
@@ -2,15 +2,44 @@ package request
 
 // 订单详情
 type OrderDetail struct {
-	ItemId      int `json:"item_id"`
-	AttrValueId int `json:"attr_value_id"`
-	PayNum      int `json:"pay_num"`
+	ItemId      int `json:"item_id" validate:"required,min=1" label:"商品"`
+	AttrValueId int `json:"attr_value_id" validate:"min=0" label:"商品规格"`
+	PayNum      int `json:"pay_num" validate:"required,min=1,max=999" label:"购买数量"`
 }
 
 // 提交订单
 type SubmitOrderReq struct {
-	Realname     string        `json:"realname"`
-	UserPhone    string        `json:"user_phone"`
-	UserAddress  string        `json:"user_address"`
-	OrderDetails []OrderDetail `json:"order_details"`
+	ShippingType uint8         `json:"shipping_type" default:"1" validate:"oneof=1 2" label:"配送方式"` // 配送方式：1快递配送，2门店自提
+	AddressId    int           `json:"address_id" validate:"min=0" label:"收货地址"`                    // 收货地址id，不为0时使用地址簿中的收货人信息
+	CouponId     int           `json:"coupon_id" validate:"min=0" label:"优惠券"`                      // 用户优惠券id
+	Realname     string        `json:"realname" validate:"max=32" label:"收货人"`
+	UserPhone    string        `json:"user_phone" validate:"regex=phone" label:"手机号"`
+	UserAddress  string        `json:"user_address" validate:"max=500" label:"收货地址"` // 门店自提时无需填写
+	OrderDetails []OrderDetail `json:"order_details" validate:"required,max=50" label:"商品"`
+}
+
+// 订单操作
+type OrderActionReq struct {
+	OrderNo string `json:"order_no" validate:"required" label:"订单号"`
+	Reason  string `json:"reason" validate:"max=200" label:"原因"`
+}
+
+// 订单支付
+type OrderPayReq struct {
+	OrderNo string `json:"order_no" validate:"required" label:"订单号"`
+	Scene   string `json:"scene" default:"applet" validate:"oneof=jsapi applet app h5 native page" label:"支付场景"`
+}
+
+// 申请退款
+type OrderRefundReq struct {
+	OrderNo             string   `json:"order_no" validate:"required" label:"订单号"`
+	RefundPrice         float64  `json:"refund_price" validate:"min=0" label:"退款金额"`            // 退款金额，为0时全额退款
+	RefundReason        string   `json:"refund_reason" validate:"max=200" label:"退款原因"`         // 退款原因
+	RefundReasonExplain string   `json:"refund_reason_explain" validate:"max=500" label:"退款说明"` // 退款说明
+	RefundReasonImg     []string `json:"refund_reason_img" validate:"max=9" label:"退款凭证"`       // 退款凭证图片
+}
+
+// 核销订单
+type ClerkVerifyReq struct {
+	Code string `json:"code" validate:"required" label:"核销码"` // 核销码或核销二维码内容
 }
@@ -2,6 +2,6 @@ package request
 
 // 分页
 type PageReq struct {
-	Page     int `query:"page" default:"1"`
-	PageSize int `query:"page_size" default:"10"`
+	Page     int `query:"page" default:"1" validate:"min=1" label:"页码"`
+	PageSize int `query:"page_size" default:"10" validate:"min=1,max=100" label:"每页数量"`
 }
@@ -0,0 +1,26 @@
+package request
+
+// 文章列表查询
+type ArticleIndexQueryReq struct {
+	PageReq
+	CategoryId int    `query:"category_id" validate:"min=0" label:"分类"`       // 分类id，包含子分类下的文章
+	Position   int    `query:"position" validate:"oneof=1 2 3 4" label:"推荐位"` // 推荐位：1首页推荐，2频道推荐，3列表推荐，4详情推荐
+	Keyword    string `query:"keyword" validate:"max=50" label:"关键字"`         // 模糊搜索：标题和标签
+}
+
+// 文章详情查询
+type ArticleDetailQueryReq struct {
+	Id       int    `query:"id" validate:"required,min=1" label:"文章"`
+	Password string `query:"password" validate:"max=200" label:"访问密码"` // 文章设置了访问密码时必填
+}
+
+// 单页详情查询
+type PageDetailQueryReq struct {
+	Name     string `query:"name" validate:"required,max=200" label:"单页"` // 单页缩略名
+	Password string `query:"password" validate:"max=200" label:"访问密码"`
+}
+
+// 分类树查询
+type CategoryTreeQueryReq struct {
+	Type string `query:"type" default:"ARTICLE" validate:"oneof=ARTICLE ITEM" label:"分类类型"`
+}
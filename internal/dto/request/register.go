@@ -0,0 +1,9 @@
+package request
+
+// 注册
+type RegisterReq struct {
+	Username string `json:"username" validate:"required,regex=username" label:"用户名"`
+	Password string `json:"password" validate:"required,min=8,max=32" label:"密码"`
+	Phone    string `json:"phone" validate:"required,regex=phone" label:"手机号"`
+	Code     string `json:"code" validate:"required,len=6" label:"验证码"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// 发送短信验证码
+type SmsSendReq struct {
+	Phone string `json:"phone" validate:"required,regex=phone" label:"手机号"`
+	Scene string `json:"scene" validate:"required,oneof=login register bind delete" label:"场景"`
+}
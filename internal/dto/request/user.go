@@ -2,7 +2,25 @@ package request
 
 // 更新用户信息
 type UpdateUserReq struct {
-	Nickname string `json:"nickname"`
-	Phone    string `json:"phone"`
-	Avatar   string `json:"avatar"`
+	Nickname string `json:"nickname" validate:"max=32" label:"昵称"`
+	Phone    string `json:"phone" validate:"regex=phone" label:"手机号"` // 修改手机号时需填写短信验证码
+	Code     string `json:"code" validate:"max=6" label:"验证码"`        // 绑定手机号短信验证码
+	Avatar   string `json:"avatar" validate:"max=1000" label:"头像"`
+}
+
+// 获取微信手机号
+type UserPhoneReq struct {
+	Code string `json:"code" validate:"required" label:"授权参数"`
+}
+
+// 修改密码
+type UserPasswordReq struct {
+	OldPassword string `json:"old_password" label:"原密码"`
+	Password    string `json:"password" validate:"required,min=8,max=32" label:"新密码"`
+}
+
+// 注销账号
+type UserDeleteReq struct {
+	Code   string `json:"code" validate:"len=6" label:"验证码"`
+	Reason string `json:"reason" validate:"max=500" label:"注销原因"`
 }
@@ -0,0 +1,14 @@
+package request
+
+// 绑定微信
+type WechatBindReq struct {
+	Platform      string `json:"platform" validate:"required,oneof=mp oa" label:"微信平台"`
+	Code          string `json:"code" validate:"required" label:"授权参数"`
+	Iv            string `json:"iv"`
+	EncryptedData string `json:"encrypted_data"`
+}
+
+// 解绑微信
+type WechatUnbindReq struct {
+	Platform string `json:"platform" validate:"required,oneof=mp oa" label:"微信平台"`
+}
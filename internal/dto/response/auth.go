@@ -0,0 +1,8 @@
+package response
+
+// 登录令牌
+type TokenResp struct {
+	Token        string `json:"token"`         // 访问令牌
+	RefreshToken string `json:"refresh_token"` // 刷新令牌
+	ExpiresIn    int    `json:"expires_in"`    // 访问令牌有效期，单位秒
+}
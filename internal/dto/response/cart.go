@@ -0,0 +1,15 @@
+package response
+
+// 购物车列表
+type CartIndexResp struct {
+	Id          int     `json:"id"`            // 购物车id
+	ItemId      int     `json:"item_id"`       // 商品id
+	AttrValueId int     `json:"attr_value_id"` // 规格id
+	Name        string  `json:"name"`          // 商品名称
+	Image       string  `json:"image"`         // 商品图片，规格图片优先
+	Suk         string  `json:"suk"`           // 规格名称
+	Price       float64 `json:"price"`         // 当前价格
+	Stock       int     `json:"stock"`         // 当前库存
+	Num         int     `json:"num"`           // 购买数量
+	IsValid     bool    `json:"is_valid"`      // 是否可购买，商品下架、规格失效或库存不足时不可购买
+}
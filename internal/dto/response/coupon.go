@@ -0,0 +1,14 @@
+package response
+
+// 可领取的优惠券
+type CouponResp struct {
+	Id          int     `json:"id"`
+	Title       string  `json:"title"`
+	Type        uint8   `json:"type"`         // 优惠券类型：1满减券，2折扣券
+	Amount      float64 `json:"amount"`       // 满减金额
+	Rate        int     `json:"rate"`         // 折扣比例，85表示85折
+	MinPrice    float64 `json:"min_price"`    // 使用门槛，0为无门槛
+	MaxDiscount float64 `json:"max_discount"` // 折扣券最高抵扣金额，0为不限制
+	ValidDays   int     `json:"valid_days"`   // 领取后有效天数
+	CanReceive  bool    `json:"can_receive"`  // 是否可以领取
+}
@@ -4,3 +4,37 @@ package response
 type SubmitOrderResp struct {
 	OrderNo string `json:"orderNo"`
 }
+
+// 订单支付返回
+type OrderPayResp struct {
+	Scene  string      `json:"scene"`
+	Params interface{} `json:"params"`
+	Url    string      `json:"url"`
+}
+
+// 订单核销码返回
+type OrderVerifyCodeResp struct {
+	VerifyCode string `json:"verify_code"`
+	Qrcode     string `json:"qrcode"` // 核销二维码内容
+}
+
+// 订单核销返回
+type ClerkVerifyResp struct {
+	OrderNo  string  `json:"order_no"`
+	TotalNum int     `json:"total_num"`
+	PayPrice float64 `json:"pay_price"`
+}
+
+// 订单物流返回
+type OrderExpressResp struct {
+	DeliveryName string                  `json:"delivery_name"` // 快递公司
+	DeliveryNo   string                  `json:"delivery_no"`   // 快递单号
+	State        string                  `json:"state"`         // 物流状态
+	Traces       []OrderExpressTraceResp `json:"traces"`        // 物流轨迹
+}
+
+// 物流轨迹
+type OrderExpressTraceResp struct {
+	Time    string `json:"time"`
+	Content string `json:"content"`
+}
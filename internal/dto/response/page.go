@@ -0,0 +1,9 @@
+package response
+
+// 分页列表
+type PageResp struct {
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Total    int64       `json:"total"`
+	List     interface{} `json:"list"`
+}
@@ -0,0 +1,61 @@
+package response
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 文章列表
+type ArticleIndexResp struct {
+	Id          int               `json:"id"`
+	CategoryId  int               `json:"category_id"`
+	Title       string            `json:"title"`
+	Author      string            `json:"author"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Tags        string            `json:"tags"`
+	ShowType    int               `json:"show_type"` // 展现形式：0无图，1单图，2多图
+	Covers      []string          `json:"covers"`
+	Link        string            `json:"link"` // 外链，不为空时直接跳转
+	View        int               `json:"view"`
+	Comment     int               `json:"comment"`
+	Locked      bool              `json:"locked"` // 是否设置了访问密码
+	CreatedAt   datetime.Datetime `json:"created_at"`
+}
+
+// 文章、单页详情
+type PostDetailResp struct {
+	Id          int               `json:"id"`
+	CategoryId  int               `json:"category_id"`
+	Title       string            `json:"title"`
+	Name        string            `json:"name"`
+	Author      string            `json:"author"`
+	Source      string            `json:"source"`
+	Description string            `json:"description"`
+	Tags        string            `json:"tags"`
+	Covers      []string          `json:"covers"`
+	Link        string            `json:"link"`
+	Content     string            `json:"content"`
+	Files       []PostFileResp    `json:"files"`
+	View        int               `json:"view"`
+	Comment     int               `json:"comment"`
+	PageTpl     string            `json:"page_tpl"` // 详情模板
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+}
+
+// 附件
+type PostFileResp struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// 分类树
+type CategoryTreeResp struct {
+	Id          int                `json:"id"`
+	Pid         int                `json:"pid"`
+	Title       string             `json:"title"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	CoverId     string             `json:"cover_id,omitempty"`
+	Children    []CategoryTreeResp `json:"children,omitempty" gorm:"-"`
+}
@@ -0,0 +1,14 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+)
+
+// 汇总前一天的账单
+func RollupBills(ctx context.Context) error {
+	_, err := service.NewBillService().Rollup(ctx, time.Now().AddDate(0, 0, -1))
+	return err
+}
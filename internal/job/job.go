@@ -0,0 +1,119 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/scheduler"
+)
+
+// 定时任务列表
+var jobs = []scheduler.Job{
+	{
+		Name:      "SyncPendingOrders",
+		Title:     "每5分钟同步一次待支付订单",
+		Spec:      "*/5 * * * *",
+		Timeout:   10 * time.Minute,
+		Singleton: true,
+		Handle:    SyncPendingOrders,
+	},
+	{
+		Name:      "CancelExpiredOrders",
+		Title:     "每分钟取消一次超时未支付的订单",
+		Spec:      "* * * * *",
+		Timeout:   5 * time.Minute,
+		Singleton: true,
+		Handle:    CancelExpiredOrders,
+	},
+	{
+		Name:      "SubmitProcessingRefunds",
+		Title:     "每10分钟重新提交一次未完成的退款单",
+		Spec:      "*/10 * * * *",
+		Timeout:   10 * time.Minute,
+		Singleton: true,
+		Handle:    SubmitProcessingRefunds,
+	},
+	{
+		Name:    "ReconcilePayBills",
+		Title:   "每天10点对账前一天的交易账单，支付平台一般在9点后生成账单",
+		Spec:    "0 10 * * *",
+		Timeout: time.Hour,
+		Handle:  ReconcilePayBills,
+	},
+	{
+		Name:    "RollupBills",
+		Title:   "每天0点10分汇总前一天的账单",
+		Spec:    "10 0 * * *",
+		Timeout: time.Hour,
+		Handle:  RollupBills,
+	},
+	{
+		Name:    "PruneJobRuns",
+		Title:   "每天3点清理30天前的任务日志",
+		Spec:    "0 3 * * *",
+		Timeout: time.Hour,
+		Handle:  PruneJobRuns,
+	},
+	{
+		Name:    "PruneUserSessions",
+		Title:   "每天3点30分清理过期的登录会话",
+		Spec:    "30 3 * * *",
+		Timeout: time.Hour,
+		Handle:  PruneUserSessions,
+	},
+	{
+		Name:      "FinalizeUserDeletions",
+		Title:     "每小时处理一次冷静期已结束的账号注销",
+		Spec:      "0 * * * *",
+		Timeout:   30 * time.Minute,
+		Singleton: true,
+		Handle:    FinalizeUserDeletions,
+	},
+}
+
+// 注册定时任务并启动调度器
+func Start() {
+	s := scheduler.NewScheduler()
+
+	// 禁用的任务不执行定时触发
+	s.BeforeRun = service.NewJobService().IsEnabled
+
+	// 保存执行记录
+	s.AfterRun = func(run scheduler.Run) {
+		if run.Err != nil {
+			log.Println("任务" + run.Name + "执行错误：" + run.Err.Error())
+		}
+		if err := service.NewJobService().Record(run); err != nil {
+			log.Println("保存任务" + run.Name + "执行记录错误：" + err.Error())
+		}
+	}
+
+	for _, job := range jobs {
+		if err := s.Register(job); err != nil {
+			log.Println(err.Error())
+		}
+	}
+	if err := service.NewJobService().Sync(s.Jobs()); err != nil {
+		log.Println("同步定时任务错误：" + err.Error())
+	}
+
+	s.Start()
+}
+
+// 清理30天前的任务日志
+func PruneJobRuns(ctx context.Context) error {
+	return service.NewJobService().PruneRuns(ctx, time.Now().AddDate(0, 0, -30))
+}
+
+// 清理过期的登录会话
+func PruneUserSessions(ctx context.Context) error {
+	return service.NewUserSessionService().PruneExpired(ctx, time.Now())
+}
+
+// 完成冷静期已结束的账号注销
+func FinalizeUserDeletions(ctx context.Context) error {
+	_, err := service.NewUserDeletionService().FinalizeDue(ctx)
+	return err
+}
@@ -0,0 +1,40 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quarkcloudio/quark-smart/v2/internal/service"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+)
+
+// 同步待支付订单
+func SyncPendingOrders(ctx context.Context) error {
+	return service.NewReconcileService().SyncPendingOrders(ctx)
+}
+
+// 取消超时未支付的订单
+func CancelExpiredOrders(ctx context.Context) error {
+	return service.NewReconcileService().CancelExpiredOrders(ctx)
+}
+
+// 重新提交未完成的退款单
+func SubmitProcessingRefunds(ctx context.Context) error {
+	return service.NewRefundService().SubmitProcessing(ctx)
+}
+
+// 对账前一天的交易账单
+func ReconcilePayBills(ctx context.Context) error {
+	date := time.Now().AddDate(0, 0, -1)
+	errs := []error{}
+	for _, channel := range []string{pay.ChannelWechat, pay.ChannelAlipay} {
+		if _, err := pay.GetGateway(channel); err != nil {
+			continue
+		}
+		if _, err := service.NewReconcileService().Reconcile(ctx, channel, date); err != nil {
+			errs = append(errs, errors.New(channel+"对账错误："+err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -39,7 +39,11 @@ func AppMiddleware(ctx *quark.Context) error {
 
 // MiniApp中间件
 func MiniAppMiddleware(ctx *quark.Context) error {
-	_, err := service.NewAuthService(ctx).GetUser()
+	authService := service.NewAuthService(ctx)
+	if err := authService.CheckSession(); err != nil {
+		return ctx.JSON(401, quark.Error(err.Error()))
+	}
+	_, err := authService.GetUser()
 	if err != nil {
 		return ctx.JSON(401, quark.Error(err.Error()))
 	}
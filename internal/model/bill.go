@@ -0,0 +1,70 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 账单收支类型
+const (
+	BillPMExpense uint8 = 0 // 支出
+	BillPMIncome  uint8 = 1 // 收入
+)
+
+// 账单类别
+const (
+	BillCategoryNowMoney = "now_money" // 余额
+	BillCategoryPay      = "pay"       // 在线支付，只记录流水不变动余额
+)
+
+// 账单明细类型
+const (
+	BillTypeRecharge  = "recharge"   // 充值
+	BillTypePayOrder  = "pay_order"  // 支付订单
+	BillTypePayRefund = "pay_refund" // 订单退款
+	BillTypeSystemAdd = "system_add" // 系统增加
+	BillTypeSystemSub = "system_sub" // 系统扣减
+)
+
+// 用户账单模型，只追加不修改，每条记录保存记账后的余额
+type Bill struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	Uid       int               `json:"uid" gorm:"size:11;not null;index"`
+	LinkId    string            `json:"link_id" gorm:"size:64;not null;default:''"`
+	BillNo    string            `json:"bill_no" gorm:"size:32;not null;uniqueIndex"`
+	PM        uint8             `json:"pm" gorm:"column:pm;size:1;not null;default:0"`
+	Title     string            `json:"title" gorm:"size:64;not null"`
+	Category  string            `json:"category" gorm:"size:64;not null"`
+	Type      string            `json:"type" gorm:"size:64;not null"`
+	Number    float64           `json:"number" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Balance   float64           `json:"balance" gorm:"type:decimal(12,2);not null;default:0.00"`
+	Mark      string            `json:"mark" gorm:"size:512;not null;default:''"`
+	Status    int8              `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt datetime.Datetime `json:"created_at" gorm:"index"`
+}
+
+// Seeder
+func (m *Bill) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(115) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 115, Name: "账单明细", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/bill/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+		{Id: 116, Name: "账单汇总", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/billRecord/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
+
+// 用户余额模型，记账时锁定该行，保证同一用户的账单串行写入
+type UserBalance struct {
+	Uid       int               `json:"uid" gorm:"primaryKey;autoIncrement:false"`
+	Balance   float64           `json:"balance" gorm:"type:decimal(12,2);not null;default:0.00"`
+	CreatedAt datetime.Datetime `json:"created_at"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 账单汇总类型
+const (
+	BillRecordTypeDay int8 = 1 // 日汇总
+)
+
+// 账单汇总模型，按平台视角汇总在线支付账单
+type BillRecord struct {
+	Id            int               `json:"id" gorm:"autoIncrement"`
+	Title         string            `json:"title" gorm:"size:64;not null"`
+	Day           string            `json:"day" gorm:"size:10;not null;uniqueIndex:idx_day_type"`
+	Type          int8              `json:"type" gorm:"size:1;not null;default:1;uniqueIndex:idx_day_type"`
+	EntryPrice    float64           `json:"entry_price" gorm:"type:decimal(12,2);not null;default:0.00"`  // 支付金额
+	ExpPrice      float64           `json:"exp_price" gorm:"type:decimal(12,2);not null;default:0.00"`    // 退款金额
+	IncomePrice   float64           `json:"income_price" gorm:"type:decimal(12,2);not null;default:0.00"` // 净收入，支付金额减退款金额
+	StartDatetime datetime.Datetime `json:"start_datetime"`
+	EndDatetime   datetime.Datetime `json:"end_datetime"`
+	Status        int8              `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt     datetime.Datetime `json:"created_at"`
+	UpdatedAt     datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 购物车模型，同一用户同一规格只保留一条记录
+type Cart struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	Uid         int               `json:"uid" gorm:"size:11;not null;uniqueIndex:idx_uid_attr_value_id"`
+	ItemId      int               `json:"item_id" gorm:"size:11;not null"`
+	AttrValueId int               `json:"attr_value_id" gorm:"size:11;not null;uniqueIndex:idx_uid_attr_value_id"`
+	Num         int               `json:"num" gorm:"size:11;not null;default:1"`
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 核销员模型，绑定小程序用户后可核销自提订单
+type Clerk struct {
+	Id         int               `json:"id" gorm:"autoIncrement"`
+	Uid        int               `json:"uid" gorm:"size:11;not null;index"`
+	Name       string            `json:"name" gorm:"size:50;not null"`
+	Phone      string            `json:"phone" gorm:"size:20;default:null"`
+	MerchantId int               `json:"merchant_id" gorm:"size:11;not null;default:0;index"`
+	Status     int               `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt  datetime.Datetime `json:"created_at"`
+	UpdatedAt  datetime.Datetime `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// Seeder
+func (m *Clerk) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(118) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 118, Name: "核销员", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/clerk/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 优惠券类型
+type CouponType uint8
+
+const (
+	CouponTypeFixed   CouponType = 1 // 满减券，满足门槛后减免固定金额
+	CouponTypePercent CouponType = 2 // 折扣券，满足门槛后按比例打折
+)
+
+// 用户优惠券状态
+type UserCouponStatus uint8
+
+const (
+	UserCouponStatusUnused UserCouponStatus = 0 // 未使用
+	UserCouponStatusUsed   UserCouponStatus = 1 // 已使用
+)
+
+// 优惠券模板模型
+type Coupon struct {
+	Id             int               `json:"id" gorm:"autoIncrement"`
+	Title          string            `json:"title" gorm:"size:100;not null"`
+	Type           CouponType        `json:"type" gorm:"size:1;not null;default:1"`
+	Amount         float64           `json:"amount" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Rate           int               `json:"rate" gorm:"size:3;not null;default:100"`
+	MinPrice       float64           `json:"min_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	MaxDiscount    float64           `json:"max_discount" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CategoryIds    string            `json:"category_ids" gorm:"size:500;default:null"`
+	Total          int               `json:"total" gorm:"size:11;not null;default:0"`
+	Issued         int               `json:"issued" gorm:"size:11;not null;default:0"`
+	PerLimit       int               `json:"per_limit" gorm:"size:11;not null;default:1"`
+	ValidDays      int               `json:"valid_days" gorm:"size:11;not null;default:30"`
+	ClaimStartTime datetime.Datetime `json:"claim_start_time"`
+	ClaimEndTime   datetime.Datetime `json:"claim_end_time"`
+	IsPublic       uint8             `json:"is_public" gorm:"size:1;not null;default:1"`
+	Status         int               `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt      datetime.Datetime `json:"created_at"`
+	UpdatedAt      datetime.Datetime `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// 用户优惠券模型，领取时保存优惠规则快照，后续修改模板不影响已领取的优惠券
+type UserCoupon struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	Uid         int               `json:"uid" gorm:"size:11;not null;index"`
+	CouponId    int               `json:"coupon_id" gorm:"size:11;not null;index"`
+	Title       string            `json:"title" gorm:"size:100;not null"`
+	Type        CouponType        `json:"type" gorm:"size:1;not null;default:1"`
+	Amount      float64           `json:"amount" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Rate        int               `json:"rate" gorm:"size:3;not null;default:100"`
+	MinPrice    float64           `json:"min_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	MaxDiscount float64           `json:"max_discount" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CategoryIds string            `json:"category_ids" gorm:"size:500;default:null"`
+	StartTime   datetime.Datetime `json:"start_time"`
+	EndTime     datetime.Datetime `json:"end_time"`
+	Status      UserCouponStatus  `json:"status" gorm:"size:1;not null;default:0"`
+	OrderId     int               `json:"order_id" gorm:"size:11;not null;default:0"`
+	UsedTime    datetime.Datetime `json:"used_time"`
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+}
+
+// Seeder
+func (m *Coupon) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(120) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 120, Name: "优惠券", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/coupon/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+		{Id: 121, Name: "领取记录", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/userCoupon/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 商品模型
+type Item struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	MerchantId  int               `json:"merchant_id" gorm:"size:11;not null;default:0"`
+	Image       string            `json:"image" gorm:"size:1000;default:null"`
+	SliderImage string            `json:"slider_image" gorm:"size:2000;default:null"`
+	Name        string            `json:"name" gorm:"size:200;not null"`
+	Keyword     string            `json:"keyword" gorm:"size:200;default:null"`
+	Description string            `json:"description" gorm:"size:500;default:null"`
+	Content     string            `json:"content" gorm:"type:text;default:null"`
+	CategoryIds string            `json:"category_ids" gorm:"size:500;default:null"`
+	Price       float64           `json:"price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	OtPrice     float64           `json:"ot_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Sort        int16             `json:"sort" gorm:"size:11;default:0;"`
+	Sales       int               `json:"sales" gorm:"size:11;not null;default:0"`
+	Stock       int               `json:"stock" gorm:"size:11;not null;default:0"`
+	Status      uint8             `json:"status" gorm:"size:1;not null;default:1"`
+	Cost        float64           `json:"cost" gorm:"type:decimal(10,2);not null;default:0.00"`
+	FictiSales  int               `json:"ficti_sales" gorm:"size:11;not null;default:0"`
+	Views       int               `json:"views" gorm:"size:11;not null;default:0"`
+	FictiViews  int               `json:"ficti_views" gorm:"size:11;not null;default:0"`
+	SpecType    uint8             `json:"spec_type" gorm:"size:1;not null;default:0"`
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// Seeder
+func (m *Item) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(110) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 110, Name: "商城管理", GuardName: "admin", Icon: "icon-shop", Type: 1, Pid: 0, Sort: 0, Path: "/shop", Show: 1, IsEngine: 0, IsLink: 0, Status: 1},
+		{Id: 111, Name: "商品列表", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/item/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+		{Id: 112, Name: "商品分类", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/itemCategory/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+
+	// 创建默认商品分类
+	seeders := []Category{
+		{Title: "默认分类", Name: "item", Type: "ITEM", Status: 1},
+	}
+	db.Client.Create(&seeders)
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 商品规格模型
+type ItemAttr struct {
+	Id         int               `json:"id" gorm:"autoIncrement"`
+	ItemId     int               `json:"item_id" gorm:"size:11;not null;index"`
+	AttrName   string            `json:"attr_name" gorm:"size:100;not null"`
+	AttrValues string            `json:"attr_values" gorm:"size:1000;not null"`
+	CreatedAt  datetime.Datetime `json:"created_at"`
+	UpdatedAt  datetime.Datetime `json:"updated_at"`
+}
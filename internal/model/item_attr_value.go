@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 商品规格属性值模型
+type ItemAttrValue struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	ItemId    int               `json:"item_id" gorm:"size:11;not null;index"`
+	Suk       string            `json:"suk" gorm:"size:200;not null"`
+	Stock     int               `json:"stock" gorm:"size:11;not null;default:0"`
+	Sales     int               `json:"sales" gorm:"size:11;not null;default:0"`
+	Price     float64           `json:"price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Image     string            `json:"image" gorm:"size:1000;default:null"`
+	Cost      float64           `json:"cost" gorm:"type:decimal(10,2);not null;default:0.00"`
+	OtPrice   float64           `json:"ot_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	AttrValue string            `json:"attr_value" gorm:"size:1000;default:null"`
+	IsDefault uint8             `json:"is_default" gorm:"size:1;not null;default:0"`
+	Status    uint8             `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt datetime.Datetime `json:"created_at"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+}
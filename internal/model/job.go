@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 任务执行结果
+const (
+	JobRunStatusNone    uint8 = 0 // 未执行
+	JobRunStatusSuccess uint8 = 1 // 成功
+	JobRunStatusFailed  uint8 = 2 // 失败
+)
+
+// 定时任务模型，任务定义在代码中注册，启动时同步到数据表
+type Job struct {
+	Id           int               `json:"id" gorm:"autoIncrement"`
+	Name         string            `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	Title        string            `json:"title" gorm:"size:200;not null"`
+	Spec         string            `json:"spec" gorm:"size:100;not null"`
+	Timeout      int               `json:"timeout" gorm:"size:11;not null;default:0"`
+	Singleton    uint8             `json:"singleton" gorm:"size:1;not null;default:0"`
+	Status       uint8             `json:"status" gorm:"size:1;not null;default:1"`
+	LastRunAt    datetime.Datetime `json:"last_run_at"`
+	LastStatus   uint8             `json:"last_status" gorm:"size:1;not null;default:0"`
+	LastDuration int               `json:"last_duration" gorm:"size:11;not null;default:0"`
+	LastError    string            `json:"last_error" gorm:"type:text"`
+	CreatedAt    datetime.Datetime `json:"created_at"`
+	UpdatedAt    datetime.Datetime `json:"updated_at"`
+}
+
+// 定时任务执行记录模型
+type JobRun struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	JobName     string            `json:"job_name" gorm:"size:100;not null;index"`
+	TriggerType string            `json:"trigger_type" gorm:"size:20;not null"`
+	Status      uint8             `json:"status" gorm:"size:1;not null;default:0"`
+	StartedAt   datetime.Datetime `json:"started_at"`
+	Duration    int               `json:"duration" gorm:"size:11;not null;default:0"`
+	Error       string            `json:"error" gorm:"type:text"`
+	CreatedAt   datetime.Datetime `json:"created_at" gorm:"index"`
+}
+
+// Seeder
+func (m *Job) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(122) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 122, Name: "定时任务", GuardName: "admin", Icon: "", Type: 2, Pid: 7, Sort: 100, Path: "/api/admin/job/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+		{Id: 123, Name: "任务日志", GuardName: "admin", Icon: "", Type: 2, Pid: 7, Sort: 100, Path: "/api/admin/jobRun/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
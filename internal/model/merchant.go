@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 商户模型，商户绑定后台管理员后，该管理员只能管理本商户的数据
+type Merchant struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	Name      string            `json:"name" gorm:"size:100;not null"`
+	Logo      string            `json:"logo" gorm:"size:1000;default:null"`
+	Contact   string            `json:"contact" gorm:"size:50;default:null"`
+	Phone     string            `json:"phone" gorm:"size:20;default:null"`
+	Address   string            `json:"address" gorm:"size:500;default:null"`
+	AdminId   int               `json:"admin_id" gorm:"size:11;not null;default:0;index"`
+	Status    int               `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt datetime.Datetime `json:"created_at"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+	DeletedAt gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// Seeder
+func (m *Merchant) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(119) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 119, Name: "商户列表", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/merchant/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
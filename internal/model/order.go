@@ -0,0 +1,123 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 订单状态
+type OrderStatus uint8
+
+const (
+	OrderStatusPendingPayment OrderStatus = 0 // 待付款
+	OrderStatusPaid           OrderStatus = 1 // 待发货
+	OrderStatusShipped        OrderStatus = 2 // 待收货
+	OrderStatusAwaitingPickup OrderStatus = 3 // 待自提
+	OrderStatusCompleted      OrderStatus = 4 // 已完成
+	OrderStatusCancelled      OrderStatus = 5 // 已取消
+	OrderStatusRefunded       OrderStatus = 6 // 已退款
+)
+
+// 订单状态名称
+var OrderStatusNames = map[OrderStatus]string{
+	OrderStatusPendingPayment: "待付款",
+	OrderStatusPaid:           "待发货",
+	OrderStatusShipped:        "待收货",
+	OrderStatusAwaitingPickup: "待自提",
+	OrderStatusCompleted:      "已完成",
+	OrderStatusCancelled:      "已取消",
+	OrderStatusRefunded:       "已退款",
+}
+
+// 退款状态
+type RefundStatus uint8
+
+const (
+	RefundStatusNone     RefundStatus = 0 // 未退款
+	RefundStatusApplying RefundStatus = 1 // 申请中
+	RefundStatusRefunded RefundStatus = 2 // 已退款
+	RefundStatusRejected RefundStatus = 3 // 已拒绝
+	RefundStatusPartial  RefundStatus = 4 // 部分退款
+)
+
+// 退款状态名称
+var RefundStatusNames = map[RefundStatus]string{
+	RefundStatusNone:     "未退款",
+	RefundStatusApplying: "申请中",
+	RefundStatusRefunded: "已退款",
+	RefundStatusRejected: "已拒绝",
+	RefundStatusPartial:  "部分退款",
+}
+
+// 配送方式
+type ShippingType uint8
+
+const (
+	ShippingTypeExpress ShippingType = 1 // 快递配送
+	ShippingTypePickup  ShippingType = 2 // 门店自提
+)
+
+// 订单模型
+type Order struct {
+	Id                    int               `json:"id" gorm:"autoIncrement"`
+	OrderNo               string            `json:"order_no" gorm:"size:32;not null;uniqueIndex"`
+	Uid                   int               `json:"uid" gorm:"size:11;not null;index"`
+	Realname              string            `json:"realname" gorm:"size:50;not null"`
+	UserPhone             string            `json:"user_phone" gorm:"size:20;not null"`
+	UserAddress           string            `json:"user_address" gorm:"size:500;not null"`
+	TotalNum              int               `json:"total_num" gorm:"size:11;not null;default:0"`
+	TotalPrice            float64           `json:"total_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	PayPrice              float64           `json:"pay_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	CouponId              int               `json:"coupon_id" gorm:"size:11;not null;default:0"`
+	CouponPrice           float64           `json:"coupon_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Discounts             string            `json:"discounts" gorm:"type:text"`
+	Paid                  uint8             `json:"paid" gorm:"size:1;not null;default:0"`
+	PayTime               datetime.Datetime `json:"pay_time"`
+	PayType               string            `json:"pay_type" gorm:"size:32;default:null"`
+	TransactionId         string            `json:"transaction_id" gorm:"size:64;default:null"`
+	Status                OrderStatus       `json:"status" gorm:"size:1;not null;default:0"`
+	RefundStatus          RefundStatus      `json:"refund_status" gorm:"size:1;not null;default:0"`
+	RefundReasonImg       string            `json:"refund_reason_img" gorm:"size:2000;default:null"`
+	RefundReasonExplain   string            `json:"refund_reason_explain" gorm:"size:500;default:null"`
+	RefundReason          string            `json:"refund_reason" gorm:"size:200;default:null"`
+	RefundRejectionReason string            `json:"refund_rejection_reason" gorm:"size:500;default:null"`
+	RefundReasonTime      datetime.Datetime `json:"refund_reason_time"`
+	RefundPrice           float64           `json:"refund_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	RefundedPrice         float64           `json:"refunded_price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Remark                string            `json:"remark" gorm:"size:500;default:null"`
+	MerchantId            int               `json:"merchant_id" gorm:"size:11;not null;default:0"`
+	IsMerchantCheck       uint8             `json:"is_merchant_check" gorm:"size:1;not null;default:0"`
+	Cost                  float64           `json:"cost" gorm:"type:decimal(10,2);not null;default:0.00"`
+	VerifyCode            string            `json:"verify_code" gorm:"size:32;default:null;uniqueIndex"`
+	ShippingType          ShippingType      `json:"shipping_type" gorm:"size:1;not null;default:1"`
+	DeliveryCode          string            `json:"delivery_code" gorm:"size:64;default:null"`
+	DeliveryName          string            `json:"delivery_name" gorm:"size:64;default:null"`
+	DeliveryNo            string            `json:"delivery_no" gorm:"size:64;default:null"`
+	DeliveryTime          datetime.Datetime `json:"delivery_time"`
+	ClerkId               int               `json:"clerk_id" gorm:"size:11;not null;default:0"`
+	CreatedAt             datetime.Datetime `json:"created_at"`
+	UpdatedAt             datetime.Datetime `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// Seeder
+func (m *Order) Seeder() {
+	menuSeeders := []*appmodel.Menu{}
+
+	// 已存在的菜单不重复创建
+	if !service.NewMenuService().IsExist(113) {
+		menuSeeders = append(menuSeeders, &appmodel.Menu{Id: 113, Name: "订单列表", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/order/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1})
+	}
+	if !service.NewMenuService().IsExist(117) {
+		menuSeeders = append(menuSeeders, &appmodel.Menu{Id: 117, Name: "退款审核", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/refund/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1})
+	}
+	if len(menuSeeders) == 0 {
+		return
+	}
+
+	// 创建菜单
+	db.Client.Create(&menuSeeders)
+}
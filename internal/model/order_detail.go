@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 订单详情模型
+type OrderDetail struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	OrderId     int               `json:"order_id" gorm:"size:11;not null;index"`
+	OrderNo     string            `json:"order_no" gorm:"size:32;not null"`
+	ItemId      int               `json:"item_id" gorm:"size:11;not null"`
+	Name        string            `json:"name" gorm:"size:200;not null"`
+	AttrValueId int               `json:"attr_value_id" gorm:"size:11;not null"`
+	Image       string            `json:"image" gorm:"size:1000;default:null"`
+	SKU         string            `json:"sku" gorm:"size:200;not null"`
+	Price       float64           `json:"price" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Cost        float64           `json:"cost" gorm:"type:decimal(10,2);not null;default:0.00"`
+	PayNum      int               `json:"pay_num" gorm:"size:11;not null;default:0"`
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+}
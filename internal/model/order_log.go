@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 订单事件
+type OrderEvent string
+
+const (
+	OrderEventPay          OrderEvent = "pay"           // 支付
+	OrderEventCancel       OrderEvent = "cancel"        // 取消
+	OrderEventShip         OrderEvent = "ship"          // 发货
+	OrderEventReadyPickup  OrderEvent = "ready_pickup"  // 备货完成，待自提
+	OrderEventReceive      OrderEvent = "receive"       // 确认收货
+	OrderEventVerify       OrderEvent = "verify"        // 核销
+	OrderEventApplyRefund  OrderEvent = "apply_refund"  // 申请退款
+	OrderEventRejectRefund OrderEvent = "reject_refund" // 拒绝退款
+	OrderEventRefund       OrderEvent = "refund"        // 退款
+	OrderEventPartRefund   OrderEvent = "part_refund"   // 部分退款
+)
+
+// 订单操作人类型
+const (
+	OrderActorUser   = "user"   // 用户
+	OrderActorAdmin  = "admin"  // 管理员
+	OrderActorClerk  = "clerk"  // 核销员
+	OrderActorSystem = "system" // 系统
+)
+
+// 订单状态流转日志模型
+type OrderLog struct {
+	Id               int               `json:"id" gorm:"autoIncrement"`
+	OrderId          int               `json:"order_id" gorm:"size:11;not null;index"`
+	Event            OrderEvent        `json:"event" gorm:"size:50;not null"`
+	FromStatus       OrderStatus       `json:"from_status" gorm:"size:1;not null"`
+	ToStatus         OrderStatus       `json:"to_status" gorm:"size:1;not null"`
+	FromRefundStatus RefundStatus      `json:"from_refund_status" gorm:"size:1;not null"`
+	ToRefundStatus   RefundStatus      `json:"to_refund_status" gorm:"size:1;not null"`
+	ActorType        string            `json:"actor_type" gorm:"size:20;not null"`
+	ActorId          int               `json:"actor_id" gorm:"size:11;not null;default:0"`
+	Reason           string            `json:"reason" gorm:"size:500;default:null"`
+	CreatedAt        datetime.Datetime `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 退款单状态
+const (
+	OrderRefundStatusProcessing uint8 = 0 // 退款中
+	OrderRefundStatusSuccess    uint8 = 1 // 退款成功
+	OrderRefundStatusFailed     uint8 = 2 // 退款失败
+)
+
+// 退款单模型，每次向支付平台发起的退款对应一条记录，一个订单可以多次部分退款
+type OrderRefund struct {
+	Id         int               `json:"id" gorm:"autoIncrement"`
+	OrderId    int               `json:"order_id" gorm:"size:11;not null;index"`
+	RefundNo   string            `json:"refund_no" gorm:"size:40;not null;uniqueIndex"`
+	RefundId   string            `json:"refund_id" gorm:"size:64;default:null"`
+	Amount     float64           `json:"amount" gorm:"type:decimal(10,2);not null;default:0.00"`
+	Reason     string            `json:"reason" gorm:"size:200;not null;default:''"`
+	ActorType  string            `json:"actor_type" gorm:"size:20;not null"`
+	ActorId    int               `json:"actor_id" gorm:"size:11;not null;default:0"`
+	Status     uint8             `json:"status" gorm:"size:1;not null;default:0;index"`
+	Error      string            `json:"error" gorm:"type:text"`
+	RefundedAt datetime.Datetime `json:"refunded_at" gorm:"index"`
+	CreatedAt  datetime.Datetime `json:"created_at"`
+	UpdatedAt  datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/service"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 支付对账报告模型
+type PayReconciliation struct {
+	Id           int               `json:"id" gorm:"autoIncrement"`
+	Channel      string            `json:"channel" gorm:"size:32;not null;uniqueIndex:idx_channel_bill_date"`
+	BillDate     string            `json:"bill_date" gorm:"size:10;not null;uniqueIndex:idx_channel_bill_date"`
+	LocalCount   int               `json:"local_count" gorm:"size:11;not null;default:0"`
+	LocalAmount  float64           `json:"local_amount" gorm:"type:decimal(12,2);not null;default:0.00"`
+	RemoteCount  int               `json:"remote_count" gorm:"size:11;not null;default:0"`
+	RemoteAmount float64           `json:"remote_amount" gorm:"type:decimal(12,2);not null;default:0.00"`
+	DiffCount    int               `json:"diff_count" gorm:"size:11;not null;default:0"`
+	Diffs        string            `json:"diffs" gorm:"type:text"`
+	Status       uint8             `json:"status" gorm:"size:1;not null;default:0"`
+	CreatedAt    datetime.Datetime `json:"created_at"`
+	UpdatedAt    datetime.Datetime `json:"updated_at"`
+}
+
+// Seeder
+func (m *PayReconciliation) Seeder() {
+
+	// 如果菜单已存在，不执行Seeder操作
+	if service.NewMenuService().IsExist(114) {
+		return
+	}
+
+	// 创建菜单
+	menuSeeders := []*appmodel.Menu{
+		{Id: 114, Name: "支付对账", GuardName: "admin", Icon: "", Type: 2, Pid: 110, Sort: 0, Path: "/api/admin/payReconciliation/index", Show: 1, IsEngine: 1, IsLink: 0, Status: 1},
+	}
+	db.Client.Create(&menuSeeders)
+}
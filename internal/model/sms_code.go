@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 短信验证码模型，未配置 Redis 时用于保存验证码和发送频率限制
+type SmsCode struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	Phone     string            `json:"phone" gorm:"size:20;not null;index:idx_phone_scene"`
+	Scene     string            `json:"scene" gorm:"size:20;not null;index:idx_phone_scene"`
+	CodeHash  string            `json:"-" gorm:"size:64;not null"`
+	Ip        string            `json:"ip" gorm:"size:64;not null;default:'';index"`
+	Attempts  int               `json:"attempts" gorm:"size:11;not null;default:0"`
+	Status    uint8             `json:"status" gorm:"size:1;not null;default:0"`
+	ExpireAt  datetime.Datetime `json:"expire_at"`
+	CreatedAt datetime.Datetime `json:"created_at" gorm:"index"`
+}
+
+// 短信发送计数模型，未配置 Redis 时按手机号、IP记录当天发送次数，发送时锁定该行
+type SmsCounter struct {
+	Name      string            `json:"name" gorm:"size:100;primaryKey"`
+	Day       string            `json:"day" gorm:"size:10;not null;default:''"`
+	Count     int               `json:"count" gorm:"size:11;not null;default:0"`
+	SentAt    datetime.Datetime `json:"sent_at" gorm:"default:null"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"gorm.io/gorm"
+)
+
+// 用户收货地址模型
+type UserAddress struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	Uid       int               `json:"uid" gorm:"size:11;not null;index"`
+	Realname  string            `json:"realname" gorm:"size:50;not null"`
+	Phone     string            `json:"phone" gorm:"size:20;not null"`
+	Province  string            `json:"province" gorm:"size:64;not null"`
+	City      string            `json:"city" gorm:"size:64;not null"`
+	District  string            `json:"district" gorm:"size:64;not null"`
+	Detail    string            `json:"detail" gorm:"size:255;not null"`
+	IsDefault uint8             `json:"is_default" gorm:"size:1;not null;default:0"`
+	CreatedAt datetime.Datetime `json:"created_at"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+	DeletedAt gorm.DeletedAt    `json:"-"`
+}
+
+// 完整地址
+func (m *UserAddress) FullAddress() string {
+	return m.Province + m.City + m.District + m.Detail
+}
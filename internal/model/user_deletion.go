@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 注销状态
+const (
+	UserDeletionStatusPending   = 0 // 冷静期中
+	UserDeletionStatusCompleted = 1 // 已注销
+	UserDeletionStatusCancelled = 2 // 已撤销
+)
+
+// 账号注销申请，冷静期结束后匿名化用户信息，保留用户记录以免订单、账单等数据失去关联
+type UserDeletion struct {
+	Id          int               `json:"id" gorm:"autoIncrement"`
+	Uid         int               `json:"uid" gorm:"size:11;not null;index"`
+	Reason      string            `json:"reason" gorm:"size:500;not null;default:''"`
+	Status      uint8             `json:"status" gorm:"size:1;not null;default:0;index"`
+	ScheduledAt datetime.Datetime `json:"scheduled_at" gorm:"index"`
+	CompletedAt datetime.Datetime `json:"completed_at"`
+	CreatedAt   datetime.Datetime `json:"created_at"`
+	UpdatedAt   datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 会话状态
+const (
+	UserSessionStatusActive  = 1 // 有效
+	UserSessionStatusRevoked = 0 // 已注销
+)
+
+// 用户登录会话，一次登录对应一个会话，刷新令牌轮换时会话不变
+type UserSession struct {
+	Id                   int               `json:"id" gorm:"autoIncrement"`
+	Uid                  int               `json:"uid" gorm:"size:11;not null;index"`
+	RefreshTokenHash     string            `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	PrevRefreshTokenHash string            `json:"-" gorm:"size:64;not null;default:'';index"`
+	Ip                   string            `json:"ip" gorm:"size:64;not null;default:''"`
+	UserAgent            string            `json:"user_agent" gorm:"size:500;not null;default:''"`
+	Status               uint8             `json:"status" gorm:"size:1;not null;default:1"`
+	ExpireAt             datetime.Datetime `json:"expire_at"`
+	RefreshedAt          datetime.Datetime `json:"refreshed_at"`
+	CreatedAt            datetime.Datetime `json:"created_at"`
+	UpdatedAt            datetime.Datetime `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+)
+
+// 微信平台
+const (
+	WechatPlatformMP = "mp" // 微信小程序
+	WechatPlatformOA = "oa" // 微信公众号
+)
+
+// 用户绑定的微信身份，同一用户在小程序和公众号的 openid 不同，通过 unionid 关联
+//
+// 解绑时保留记录并将状态置为0，登录时不再通过该身份及其 unionid 合并到原用户
+type UserWechat struct {
+	Id        int               `json:"id" gorm:"autoIncrement"`
+	Uid       int               `json:"uid" gorm:"size:11;not null;index"`
+	Platform  string            `json:"platform" gorm:"size:10;not null;uniqueIndex:idx_platform_openid"`
+	Openid    string            `json:"openid" gorm:"size:100;not null;uniqueIndex:idx_platform_openid"`
+	Unionid   string            `json:"unionid" gorm:"size:100;not null;default:'';index"`
+	Nickname  string            `json:"nickname" gorm:"size:200"`
+	Avatar    string            `json:"avatar" gorm:"size:1000"`
+	Status    int8              `json:"status" gorm:"size:1;not null;default:1"`
+	CreatedAt datetime.Datetime `json:"created_at"`
+	UpdatedAt datetime.Datetime `json:"updated_at"`
+}
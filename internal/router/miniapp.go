@@ -14,14 +14,73 @@ func MiniAppRegister(b *quark.Engine) {
 	g.GET("/index/index", (&handler.Index{}).Index)
 	g.POST("/register/index", (&handler.Register{}).Index)
 	g.POST("/login/index", (&handler.Login{}).Index)
+	g.POST("/login/phone", (&handler.Login{}).Phone)
+	g.POST("/login/wechatMP", (&handler.Login{}).WechatMP) // 微信小程序登录
+	g.POST("/login/wechatOA", (&handler.Login{}).WechatOA) // 微信公众号登录
 	g.GET("/login/mock", (&handler.Login{}).Mock)
+	g.POST("/auth/refresh", (&handler.Auth{}).Refresh) // 刷新令牌
+	g.POST("/sms/send", (&handler.Sms{}).Send)         // 发送短信验证码
 
 	// 轮播组
 	g.GET("/index/banner", (&handler.Index{}).Banner) // 轮播列表
 
+	// 商品组
+	g.GET("/item/index", (&handler.Item{}).Index)           // 商品列表
+	g.GET("/item/detail", (&handler.Item{}).Detail)         // 商品详情
+	g.GET("/item/categories", (&handler.Item{}).Categories) // 商品分类
+
+	// 内容组
+	g.GET("/article/index", (&handler.Article{}).Index)   // 文章列表
+	g.GET("/article/detail", (&handler.Article{}).Detail) // 文章详情
+	g.GET("/page/detail", (&handler.Page{}).Detail)       // 单页详情
+	g.GET("/category/tree", (&handler.Category{}).Tree)   // 分类树
+
 	// 需要登录认证路由组
 	ag := b.Group("/api/miniapp", middleware.MiniAppMiddleware)
+	ag.POST("/auth/logout", (&handler.Auth{}).Logout)       // 退出登录
+	ag.POST("/auth/logoutAll", (&handler.Auth{}).LogoutAll) // 退出所有设备
 	ag.GET("/user/index", (&handler.User{}).Index)
 	ag.POST("/user/save", (&handler.User{}).Save)
+	ag.POST("/user/phone", (&handler.User{}).Phone)       // 绑定微信手机号
+	ag.POST("/user/password", (&handler.User{}).Password) // 修改密码
 	ag.POST("/user/delete", (&handler.User{}).Delete)
+
+	// 微信绑定组
+	ag.GET("/wechat/index", (&handler.Wechat{}).Index)    // 已绑定的微信
+	ag.POST("/wechat/bind", (&handler.Wechat{}).Bind)     // 绑定微信
+	ag.POST("/wechat/unbind", (&handler.Wechat{}).Unbind) // 解绑微信
+
+	// 订单组
+	ag.GET("/order/detail", (&handler.Order{}).Detail)         // 订单详情
+	ag.POST("/order/submit", (&handler.Order{}).Submit)        // 提交订单
+	ag.POST("/order/pay", (&handler.Order{}).Pay)              // 订单支付
+	ag.POST("/order/cancel", (&handler.Order{}).Cancel)        // 取消订单
+	ag.POST("/order/receive", (&handler.Order{}).Receive)      // 确认收货
+	ag.POST("/order/refund", (&handler.Order{}).Refund)        // 申请退款
+	ag.GET("/order/verifyCode", (&handler.Order{}).VerifyCode) // 订单核销码
+	ag.GET("/order/express", (&handler.Order{}).Express)       // 订单物流
+
+	// 购物车组
+	ag.GET("/cart/index", (&handler.Cart{}).Index)        // 购物车列表
+	ag.POST("/cart/add", (&handler.Cart{}).Add)           // 加入购物车
+	ag.POST("/cart/update", (&handler.Cart{}).Update)     // 修改数量
+	ag.POST("/cart/delete", (&handler.Cart{}).Delete)     // 删除商品
+	ag.POST("/cart/checkout", (&handler.Cart{}).Checkout) // 结算下单
+
+	// 收货地址组
+	ag.GET("/address/index", (&handler.Address{}).Index)            // 收货地址列表
+	ag.POST("/address/save", (&handler.Address{}).Save)             // 保存收货地址
+	ag.POST("/address/setDefault", (&handler.Address{}).SetDefault) // 设置默认地址
+	ag.POST("/address/delete", (&handler.Address{}).Delete)         // 删除收货地址
+
+	// 优惠券组
+	ag.GET("/coupon/index", (&handler.Coupon{}).Index)      // 可领取的优惠券
+	ag.POST("/coupon/receive", (&handler.Coupon{}).Receive) // 领取优惠券
+	ag.GET("/coupon/my", (&handler.Coupon{}).My)            // 我的优惠券
+
+	// 核销组
+	ag.POST("/clerk/verify", (&handler.Clerk{}).Verify) // 核销订单
+
+	// 账单组
+	ag.GET("/bill/index", (&handler.Bill{}).Index) // 账单列表
 }
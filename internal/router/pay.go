@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/quarkcloudio/quark-go/v3"
+	"github.com/quarkcloudio/quark-smart/v2/internal/app/pay/handler"
+)
+
+// 注册支付路由
+func PayRegister(b *quark.Engine) {
+	g := b.Group("/api/pay")
+	g.POST("/wechat/notify", (&handler.Notify{}).Wechat) // 微信支付通知
+	g.POST("/alipay/notify", (&handler.Notify{}).Alipay) // 支付宝通知
+}
@@ -8,4 +8,8 @@ import (
 // 注册Web路由
 func WebRegister(b *quark.Engine) {
 	b.GET("/", (&home.Index{}).Index)
+	b.GET("/channel/:category", (&home.Category{}).Channel) // 频道页，分类ID或缩略名
+	b.GET("/list/:category", (&home.Category{}).List)       // 列表页，分类ID或缩略名
+	b.GET("/article/:id", (&home.Article{}).Detail)         // 文章详情
+	b.GET("/page/:name", (&home.Page{}).Detail)             // 单页详情
 }
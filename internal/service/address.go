@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+)
+
+// 收货地址最大数量
+const AddressLimit = 20
+
+type AddressService struct{}
+
+func NewAddressService() *AddressService {
+	return &AddressService{}
+}
+
+// 获取用户收货地址列表，默认地址排在最前
+func (p *AddressService) GetList(uid int) (list []model.UserAddress, err error) {
+	list = make([]model.UserAddress, 0)
+	err = db.Client.Where("uid = ?", uid).Order("is_default desc, id desc").Find(&list).Error
+	return list, err
+}
+
+// 获取用户的收货地址
+func (p *AddressService) GetUserAddress(uid int, id int) (address model.UserAddress, err error) {
+	err = db.Client.Where("id = ?", id).Where("uid = ?", uid).First(&address).Error
+	if err != nil {
+		return address, errors.New("收货地址不存在")
+	}
+	return address, nil
+}
+
+// 获取用户的默认收货地址
+func (p *AddressService) GetDefault(uid int) (address model.UserAddress, err error) {
+	err = db.Client.Where("uid = ?", uid).Where("is_default = ?", 1).First(&address).Error
+	return address, err
+}
+
+// 保存收货地址，第一个地址自动设为默认地址
+func (p *AddressService) Save(uid int, param request.AddressSaveReq) (address model.UserAddress, err error) {
+	if param.Realname == "" || param.Phone == "" {
+		return address, errors.New("收货人信息不能为空")
+	}
+	if param.Province == "" || param.City == "" || param.District == "" || param.Detail == "" {
+		return address, errors.New("请填写完整的收货地址")
+	}
+
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.UserAddress{}).Where("uid = ?", uid).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if param.Id > 0 {
+			if err := tx.Where("id = ?", param.Id).Where("uid = ?", uid).First(&address).Error; err != nil {
+				return errors.New("收货地址不存在")
+			}
+		} else if count >= AddressLimit {
+			return errors.New("收货地址最多添加20个")
+		}
+
+		address.Uid = uid
+		address.Realname = param.Realname
+		address.Phone = param.Phone
+		address.Province = param.Province
+		address.City = param.City
+		address.District = param.District
+		address.Detail = param.Detail
+		if param.IsDefault || count == 0 {
+			address.IsDefault = 1
+		}
+		if err := tx.Save(&address).Error; err != nil {
+			return err
+		}
+
+		if address.IsDefault == 1 {
+			return p.resetDefault(tx, uid, address.Id)
+		}
+		return nil
+	})
+
+	return address, err
+}
+
+// 设置默认收货地址
+func (p *AddressService) SetDefault(uid int, id int) error {
+	address, err := p.GetUserAddress(uid, id)
+	if err != nil {
+		return err
+	}
+
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserAddress{}).Where("id = ?", address.Id).Update("is_default", 1).Error; err != nil {
+			return err
+		}
+		return p.resetDefault(tx, uid, address.Id)
+	})
+}
+
+// 删除收货地址，删除默认地址后将最新的地址设为默认地址
+func (p *AddressService) Delete(uid int, id int) error {
+	address, err := p.GetUserAddress(uid, id)
+	if err != nil {
+		return err
+	}
+
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&address).Error; err != nil {
+			return err
+		}
+		if address.IsDefault != 1 {
+			return nil
+		}
+
+		latest := model.UserAddress{}
+		tx.Where("uid = ?", uid).Order("id desc").Limit(1).Find(&latest)
+		if latest.Id == 0 {
+			return nil
+		}
+		return tx.Model(&model.UserAddress{}).Where("id = ?", latest.Id).Update("is_default", 1).Error
+	})
+}
+
+// 取消其他地址的默认状态
+func (p *AddressService) resetDefault(tx *gorm.DB, uid int, id int) error {
+	return tx.Model(&model.UserAddress{}).
+		Where("uid = ?", uid).
+		Where("id <> ?", id).
+		Where("is_default = ?", 1).
+		Update("is_default", 0).Error
+}
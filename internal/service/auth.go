@@ -2,13 +2,21 @@ package service
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/quarkcloudio/quark-go/v3"
-	"github.com/quarkcloudio/quark-go/v3/model"
+	quarkdto "github.com/quarkcloudio/quark-go/v3/dto"
+	quarkmodel "github.com/quarkcloudio/quark-go/v3/model"
 	appservice "github.com/quarkcloudio/quark-go/v3/service"
 	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-go/v3/utils/hash"
 	"github.com/quarkcloudio/quark-smart/v2/config"
 	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/sms"
 	"github.com/quarkcloudio/quark-smart/v2/pkg/wechat"
 )
 
@@ -21,7 +29,7 @@ func NewAuthService(ctx *quark.Context) *AuthService {
 }
 
 // 获取当前登录用户信息
-func (p *AuthService) GetUser() (user model.User, err error) {
+func (p *AuthService) GetUser() (user quarkmodel.User, err error) {
 	return appservice.NewAuthService(p.ctx).GetUser()
 }
 
@@ -31,47 +39,190 @@ func (p *AuthService) GetUid() (userId int, err error) {
 }
 
 // 模拟登录
-func (p *AuthService) MockLogin() (token string, err error) {
+func (p *AuthService) MockLogin() (token response.TokenResp, err error) {
 	if !(config.App.Env == "develop" || config.App.Env == "dev" || config.App.Env == "development") {
-		return "", errors.New("it must be a development environment")
+		return token, errors.New("it must be a development environment")
 	}
 	uid := p.ctx.Query("uid", 1)
 	user, err := NewUserService().GetInfoById(uid)
 	if err != nil {
-		return "", err
+		return token, err
 	}
-	return appservice.NewAuthService(p.ctx).MakeToken(user, "user", 60*24*60*60)
+	return p.IssueToken(user)
 }
 
 // 账号密码授权
-func (p *AuthService) Login(username, password string) (token string, err error) {
-	return appservice.NewAuthService(p.ctx).UserLogin(username, password)
+func (p *AuthService) Login(username, password string) (token response.TokenResp, err error) {
+	userService := NewUserService()
+	user, err := userService.GetInfoByUsername(username)
+	if err != nil || user.Password == "" || !hash.Check(user.Password, password) {
+		return token, errors.New("用户名或密码错误")
+	}
+	if user.Status == 0 {
+		return token, errors.New("用户已被禁用")
+	}
+	if err := userService.UpdateLastLogin(user.Id, p.ctx.ClientIP(), datetime.Now()); err != nil {
+		return token, err
+	}
+
+	return p.IssueToken(user)
 }
 
-// 微信小程序授权
-func (p *AuthService) WechatMPLogin(param dto.WechatAuthDTO) (token string, err error) {
-	// 获取微信授权用户信息
-	wechatUser, err := wechat.NewWechatMiniProgram().GetWechatUser(param.Iv, param.Code, param.EncryptedData)
+// 签发访问令牌和刷新令牌，每次登录创建一个新会话
+//
+// 注销冷静期内重新登录视为撤销注销
+func (p *AuthService) IssueToken(user quarkmodel.User) (token response.TokenResp, err error) {
+	if err := NewUserDeletionService().Cancel(user.Id); err != nil {
+		return token, err
+	}
+	session, refreshToken, err := NewUserSessionService().Create(user.Id, p.ctx.ClientIP(), p.ctx.Header("User-Agent"))
 	if err != nil {
 		return token, err
 	}
+	return p.makeToken(user, session.Id, refreshToken)
+}
 
-	// 初始化用户服务层
+// 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+func (p *AuthService) Refresh(refreshToken string) (token response.TokenResp, err error) {
+	session, refreshToken, err := NewUserSessionService().Rotate(refreshToken, p.ctx.ClientIP())
+	if err != nil {
+		return token, err
+	}
+	user, err := NewUserService().GetInfoById(session.Uid)
+	if err != nil {
+		return token, ErrRefreshTokenInvalid
+	}
+	if user.Status == 0 {
+		return token, errors.New("用户已被禁用")
+	}
+	return p.makeToken(user, session.Id, refreshToken)
+}
+
+// 生成访问令牌，令牌ID为会话ID，用于注销后校验
+func (p *AuthService) makeToken(user quarkmodel.User, sessionId int, refreshToken string) (token response.TokenResp, err error) {
+	ttl := NewUserSessionService().GetAccessTokenTTL()
+	now := time.Now()
+	accessToken, err := p.ctx.JwtToken(&quarkdto.UserClaims{
+		Id:        user.Id,
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		Sex:       user.Sex,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Avatar:    user.Avatar,
+		GuardName: "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(sessionId),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "QuarkCloud",
+			Subject:   "UserToken",
+		},
+	})
+	if err != nil {
+		return token, err
+	}
+
+	return response.TokenResp{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(ttl.Seconds()),
+	}, nil
+}
+
+// 获取当前访问令牌的会话ID
+func (p *AuthService) GetSessionId() (sessionId int, err error) {
+	claims, err := p.ctx.JwtAuthUserMap()
+	if err != nil {
+		return 0, err
+	}
+	if guardName, _ := claims["guard_name"].(string); guardName != "user" {
+		return 0, errors.New("登录已失效，请重新登录")
+	}
+	jti, _ := claims["jti"].(string)
+	sessionId, err = strconv.Atoi(jti)
+	if err != nil || sessionId <= 0 {
+		return 0, errors.New("登录已失效，请重新登录")
+	}
+	return sessionId, nil
+}
+
+// 校验当前登录会话，会话已注销时返回错误
+func (p *AuthService) CheckSession() error {
+	sessionId, err := p.GetSessionId()
+	if err != nil {
+		return err
+	}
+	if NewUserSessionService().IsRevoked(sessionId) {
+		return errors.New("登录已失效，请重新登录")
+	}
+	return nil
+}
+
+// 退出登录，注销当前会话
+func (p *AuthService) Logout() error {
+	sessionId, err := p.GetSessionId()
+	if err != nil {
+		return err
+	}
+	return NewUserSessionService().Revoke(sessionId)
+}
+
+// 退出所有设备，注销当前用户的全部会话
+func (p *AuthService) LogoutAll() error {
+	uid, err := p.GetUid()
+	if err != nil {
+		return err
+	}
+	return NewUserSessionService().RevokeAll(uid)
+}
+
+// 修改密码，修改后退出所有设备并为当前设备签发新令牌
+//
+// 未设置过密码的用户无需验证原密码
+func (p *AuthService) ChangePassword(oldPassword string, password string) (token response.TokenResp, err error) {
+	user, err := p.GetUser()
+	if err != nil {
+		return token, err
+	}
+	if user.Password != "" && !hash.Check(user.Password, oldPassword) {
+		return token, errors.New("原密码错误")
+	}
 	userService := NewUserService()
-	user := userService.GetInfoByWxOpenid(wechatUser.OpenID)
-	if user.Id > 0 {
-		user, err = userService.UpdateUser(dto.SaveUserDTO{
-			Id:            user.Id,
-			LastLoginIp:   p.ctx.ClientIP(),
-			LastLoginTime: datetime.Now(),
-		})
+	if err := userService.CheckPassword(password); err != nil {
+		return token, err
+	}
+	if err := userService.UpdatePassword(user.Id, hash.Make(password)); err != nil {
+		return token, err
+	}
+	if err := NewUserSessionService().RevokeAll(user.Id); err != nil {
+		return token, err
+	}
+
+	return p.IssueToken(user)
+}
+
+// 手机号验证码登录，手机号未注册时自动注册
+func (p *AuthService) PhoneLogin(phone string, code string) (token response.TokenResp, err error) {
+	if err := sms.CheckPhone(phone); err != nil {
+		return token, err
+	}
+	if err := NewSmsCodeService().Verify(phone, SmsSceneLogin, code); err != nil {
+		return token, err
+	}
+
+	userService := NewUserService()
+	user, err := userService.GetInfoByPhone(phone)
+	if err == nil {
+		if user.Status == 0 {
+			return token, errors.New("用户已被禁用")
+		}
+		err = userService.UpdateLastLogin(user.Id, p.ctx.ClientIP(), datetime.Now())
 	} else {
 		user, err = userService.CreateUser(dto.SaveUserDTO{
-			Nickname:      wechatUser.NickName,
-			Sex:           wechatUser.Gender,
-			Avatar:        wechatUser.AvatarURL,
-			WxOpenid:      wechatUser.OpenID,
-			WxUnionid:     wechatUser.UnionID,
+			Nickname:      phone[:3] + "****" + phone[7:],
+			Phone:         phone,
 			LastLoginIp:   p.ctx.ClientIP(),
 			LastLoginTime: datetime.Now(),
 		})
@@ -80,33 +231,107 @@ func (p *AuthService) WechatMPLogin(param dto.WechatAuthDTO) (token string, err
 		return token, err
 	}
 
-	return appservice.NewAuthService(p.ctx).MakeToken(user, "user", 24*60*60)
+	return p.IssueToken(user)
 }
 
-// 微信网页授权
-func (p *AuthService) WechatOALogin(param dto.WechatAuthDTO) (token string, err error) {
-	// 获取微信授权用户信息
-	wechatUser, err := wechat.NewWechatOfficialAccount().GetWechatUser(p.ctx.Request.Context(), param.Code)
+// 用户名密码注册，手机号需通过短信验证码验证，注册成功后直接登录
+func (p *AuthService) Register(username string, password string, phone string, code string) (token response.TokenResp, err error) {
+	userService := NewUserService()
+	if err := userService.CheckUsername(username); err != nil {
+		return token, err
+	}
+	if err := userService.CheckPassword(password); err != nil {
+		return token, err
+	}
+	if err := sms.CheckPhone(phone); err != nil {
+		return token, err
+	}
+	if userService.IsUsernameExist(username) {
+		return token, errors.New("用户名已被注册")
+	}
+	if userService.IsPhoneExist(phone) {
+		return token, errors.New("手机号已被注册")
+	}
+	if err := NewSmsCodeService().Verify(phone, SmsSceneRegister, code); err != nil {
+		return token, err
+	}
+
+	user, err := userService.CreateUser(dto.SaveUserDTO{
+		Username:      username,
+		Nickname:      username,
+		Phone:         phone,
+		Password:      hash.Make(password),
+		LastLoginIp:   p.ctx.ClientIP(),
+		LastLoginTime: datetime.Now(),
+	})
+	if err != nil {
+		return token, errors.New("注册失败，用户名或手机号已被注册")
+	}
+
+	return p.IssueToken(user)
+}
+
+// 通过授权参数获取微信身份
+func (p *AuthService) GetWechatIdentity(platform string, param dto.WechatAuthDTO) (identity dto.WechatIdentityDTO, err error) {
+	switch platform {
+	case model.WechatPlatformMP:
+		wechatUser, err := wechat.NewWechatMiniProgram().GetWechatUser(param.Iv, param.Code, param.EncryptedData)
+		if err != nil {
+			return identity, err
+		}
+		identity = dto.WechatIdentityDTO{
+			Openid:   wechatUser.OpenID,
+			Unionid:  wechatUser.UnionID,
+			Nickname: wechatUser.NickName,
+			Avatar:   wechatUser.AvatarURL,
+			Sex:      wechatUser.Gender,
+		}
+	case model.WechatPlatformOA:
+		wechatUser, err := wechat.NewWechatOfficialAccount().GetWechatUser(p.ctx.Request.Context(), param.Code)
+		if err != nil {
+			return identity, err
+		}
+		identity = dto.WechatIdentityDTO{
+			Openid:   wechatUser.OpenID,
+			Unionid:  wechatUser.Unionid,
+			Nickname: wechatUser.Nickname,
+			Avatar:   wechatUser.HeadImgURL,
+			Sex:      int(wechatUser.Sex),
+		}
+	default:
+		return identity, errors.New("不支持的微信平台")
+	}
+	if identity.Openid == "" {
+		return identity, errors.New("微信授权失败")
+	}
+	identity.Platform = platform
+	return identity, nil
+}
+
+// 微信授权登录，同一开放平台下的小程序和公众号通过 unionid 合并为同一用户
+func (p *AuthService) WechatLogin(platform string, param dto.WechatAuthDTO) (token response.TokenResp, err error) {
+	identity, err := p.GetWechatIdentity(platform, param)
 	if err != nil {
 		return token, err
 	}
 
-	// 初始化用户服务层
 	userService := NewUserService()
-	user := userService.GetInfoByWxOpenid(wechatUser.OpenID)
-	if user.Id > 0 {
-		user, err = userService.UpdateUser(dto.SaveUserDTO{
-			Id:            user.Id,
-			LastLoginIp:   p.ctx.ClientIP(),
-			LastLoginTime: datetime.Now(),
-		})
+	userWechatService := NewUserWechatService()
+	var user quarkmodel.User
+	if uid := userWechatService.GetUidByIdentity(identity); uid > 0 {
+		user, err = userService.GetInfoById(uid)
+		if err != nil {
+			return token, err
+		}
+		if user.Status == 0 {
+			return token, errors.New("用户已被禁用")
+		}
+		err = userService.UpdateLastLogin(user.Id, p.ctx.ClientIP(), datetime.Now())
 	} else {
 		user, err = userService.CreateUser(dto.SaveUserDTO{
-			Nickname:      wechatUser.Nickname,
-			Sex:           int(wechatUser.Sex),
-			Avatar:        wechatUser.HeadImgURL,
-			WxOpenid:      wechatUser.OpenID,
-			WxUnionid:     wechatUser.Unionid,
+			Nickname:      identity.Nickname,
+			Sex:           identity.Sex,
+			Avatar:        identity.Avatar,
 			LastLoginIp:   p.ctx.ClientIP(),
 			LastLoginTime: datetime.Now(),
 		})
@@ -114,6 +339,32 @@ func (p *AuthService) WechatOALogin(param dto.WechatAuthDTO) (token string, err
 	if err != nil {
 		return token, err
 	}
+	if err := userWechatService.Save(user.Id, identity); err != nil {
+		return token, err
+	}
+
+	return p.IssueToken(user)
+}
 
-	return appservice.NewAuthService(p.ctx).MakeToken(user, "user", 24*60*60)
+// 微信小程序授权
+func (p *AuthService) WechatMPLogin(param dto.WechatAuthDTO) (token response.TokenResp, err error) {
+	return p.WechatLogin(model.WechatPlatformMP, param)
+}
+
+// 微信网页授权
+func (p *AuthService) WechatOALogin(param dto.WechatAuthDTO) (token response.TokenResp, err error) {
+	return p.WechatLogin(model.WechatPlatformOA, param)
+}
+
+// 当前登录用户绑定微信
+func (p *AuthService) WechatBind(platform string, param dto.WechatAuthDTO) error {
+	uid, err := p.GetUid()
+	if err != nil {
+		return err
+	}
+	identity, err := p.GetWechatIdentity(platform, param)
+	if err != nil {
+		return err
+	}
+	return NewUserWechatService().Bind(uid, identity)
 }
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BillService struct{}
+
+func NewBillService() *BillService {
+	return &BillService{}
+}
+
+// 获取用户余额
+func (p *BillService) GetBalance(uid int) (float64, error) {
+	balance := model.UserBalance{}
+	err := db.Client.Where("uid = ?", uid).Limit(1).Find(&balance).Error
+	return balance.Balance, err
+}
+
+// 增加余额
+func (p *BillService) Income(param dto.BillPostDTO) (model.Bill, error) {
+	param.PM = model.BillPMIncome
+	return p.Post(param)
+}
+
+// 扣减余额
+func (p *BillService) Expense(param dto.BillPostDTO) (model.Bill, error) {
+	param.PM = model.BillPMExpense
+	return p.Post(param)
+}
+
+// 记账
+func (p *BillService) Post(param dto.BillPostDTO) (bill model.Bill, err error) {
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		bill, err = p.PostWithTx(tx, param)
+		return err
+	})
+	return bill, err
+}
+
+// 在已有事务中记账，锁定用户余额行后写入账单，余额类别的账单同时更新余额
+func (p *BillService) PostWithTx(tx *gorm.DB, param dto.BillPostDTO) (model.Bill, error) {
+	bill := model.Bill{}
+	param.Number = roundPrice(param.Number)
+	if param.Uid <= 0 || param.Number <= 0 {
+		return bill, errors.New("记账参数错误")
+	}
+	if param.PM != model.BillPMIncome && param.PM != model.BillPMExpense {
+		return bill, errors.New("收支类型错误")
+	}
+	if param.Category == "" {
+		param.Category = model.BillCategoryNowMoney
+	}
+
+	// 余额行不存在时先创建，再加锁
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.UserBalance{Uid: param.Uid}).Error
+	if err != nil {
+		return bill, err
+	}
+	balance := model.UserBalance{}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uid = ?", param.Uid).First(&balance).Error
+	if err != nil {
+		return bill, err
+	}
+
+	// 关联业务已记过账的直接返回，防止重复记账
+	if param.LinkId != "" {
+		tx.Where("uid = ? AND type = ? AND link_id = ?", param.Uid, param.Type, param.LinkId).Limit(1).Find(&bill)
+		if bill.Id > 0 {
+			return bill, nil
+		}
+	}
+
+	// 在线支付等非余额类别只记录流水，账单保存当前余额
+	newBalance := balance.Balance
+	if param.Category == model.BillCategoryNowMoney {
+		newBalance = roundPrice(balance.Balance + param.Number)
+		if param.PM == model.BillPMExpense {
+			newBalance = roundPrice(balance.Balance - param.Number)
+			if newBalance < 0 {
+				return bill, errors.New("余额不足")
+			}
+		}
+	}
+
+	billNo, err := p.makeBillNo(tx)
+	if err != nil {
+		return bill, err
+	}
+	bill = model.Bill{
+		Uid:      param.Uid,
+		LinkId:   param.LinkId,
+		BillNo:   billNo,
+		PM:       param.PM,
+		Title:    param.Title,
+		Category: param.Category,
+		Type:     param.Type,
+		Number:   param.Number,
+		Balance:  newBalance,
+		Mark:     param.Mark,
+		Status:   1,
+	}
+	if err = tx.Create(&bill).Error; err != nil {
+		return bill, err
+	}
+	if param.Category != model.BillCategoryNowMoney {
+		return bill, nil
+	}
+
+	err = tx.Model(&model.UserBalance{}).Where("uid = ?", param.Uid).Update("balance", newBalance).Error
+	return bill, err
+}
+
+// 获取用户余额账单分页列表
+func (p *BillService) GetPage(uid int, param request.BillIndexQueryReq) (list []response.BillDetailResp, total int64, err error) {
+	list = make([]response.BillDetailResp, 0)
+	query := db.Client.Model(&model.Bill{}).Where("uid = ? AND category = ?", uid, model.BillCategoryNowMoney)
+
+	// 收支筛选
+	if param.PM == "0" || param.PM == "1" {
+		query = query.Where("pm = ?", param.PM)
+	}
+
+	if err = query.Count(&total).Error; err != nil {
+		return list, total, err
+	}
+
+	page, pageSize := param.Page, param.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	err = query.
+		Order("id desc").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&list).Error
+	return list, total, err
+}
+
+// 汇总指定日期的在线支付账单，重复汇总时覆盖之前的结果
+//
+// 汇总为平台视角：用户支付订单为平台收入，订单退款为平台支出，净收入为支付减退款；余额充值等余额账单不计入
+func (p *BillService) Rollup(ctx context.Context, date time.Time) (model.BillRecord, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+
+	sums := []struct {
+		PM    uint8
+		Total float64
+	}{}
+	err := db.Client.WithContext(ctx).Model(&model.Bill{}).
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Where("category = ? AND status = ?", model.BillCategoryPay, 1).
+		Group("pm").
+		Select("pm, SUM(number) AS total").
+		Scan(&sums).Error
+	if err != nil {
+		return model.BillRecord{}, err
+	}
+
+	record := model.BillRecord{
+		Title:         start.Format(time.DateOnly) + "账单",
+		Day:           start.Format(time.DateOnly),
+		Type:          model.BillRecordTypeDay,
+		StartDatetime: datetime.Datetime{Time: start},
+		EndDatetime:   datetime.Datetime{Time: end.Add(-time.Second)},
+		Status:        1,
+	}
+	for _, sum := range sums {
+		// 账单收支是用户视角，与平台视角相反
+		if sum.PM == model.BillPMExpense {
+			record.EntryPrice = roundPrice(sum.Total)
+		} else {
+			record.ExpPrice = roundPrice(sum.Total)
+		}
+	}
+	record.IncomePrice = roundPrice(record.EntryPrice - record.ExpPrice)
+
+	err = db.Client.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "day"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entry_price", "exp_price", "income_price", "updated_at"}),
+	}).Create(&record).Error
+	return record, err
+}
+
+// 生成账单号
+func (p *BillService) makeBillNo(tx *gorm.DB) (string, error) {
+	for i := 0; i < 5; i++ {
+		billNo := "B" + time.Now().Format("20060102150405") + rand.MakeNumeric(8)
+		var count int64
+		if err := tx.Model(&model.Bill{}).Where("bill_no = ?", billNo).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return billNo, nil
+		}
+	}
+	return "", errors.New("生成账单号失败，请重试")
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+func TestBillRollup(t *testing.T) {
+	setupDB(t, &model.UserBalance{}, &model.Bill{}, &model.BillRecord{})
+
+	bills := []dto.BillPostDTO{
+		{Uid: 1, PM: model.BillPMIncome, Title: "余额充值", Type: model.BillTypeRecharge, Number: 100},
+		{Uid: 1, PM: model.BillPMExpense, Title: "购买商品", Category: model.BillCategoryPay, Type: model.BillTypePayOrder, Number: 30, LinkId: "O001"},
+		{Uid: 1, PM: model.BillPMIncome, Title: "订单退款", Category: model.BillCategoryPay, Type: model.BillTypePayRefund, Number: 10.5, LinkId: "O001R1"},
+	}
+	for _, bill := range bills {
+		if _, err := NewBillService().Post(bill); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 余额充值不计入，支付为收入，退款为支出
+	record, err := NewBillService().Rollup(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.EntryPrice != 30 || record.ExpPrice != 10.5 || record.IncomePrice != 19.5 {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+
+	// 重复汇总覆盖之前的结果
+	if _, err = NewBillService().Rollup(context.Background(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Client.Model(&model.BillRecord{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 record, got %d", count)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 购物车最大商品条数
+const CartLimit = 100
+
+type CartService struct{}
+
+func NewCartService() *CartService {
+	return &CartService{}
+}
+
+// 获取购物车列表，价格和库存以商品当前信息为准
+func (p *CartService) GetList(uid int) (list []response.CartIndexResp, err error) {
+	list = make([]response.CartIndexResp, 0)
+	carts := []model.Cart{}
+	if err = db.Client.Where("uid = ?", uid).Order("updated_at desc, id desc").Find(&carts).Error; err != nil {
+		return list, err
+	}
+
+	for _, cart := range carts {
+		row := response.CartIndexResp{
+			Id:          cart.Id,
+			ItemId:      cart.ItemId,
+			AttrValueId: cart.AttrValueId,
+			Num:         cart.Num,
+		}
+
+		item, attrValue, err := p.getSku(db.Client, cart.ItemId, cart.AttrValueId)
+		if err == nil {
+			row.Name = item.Name
+			row.Image = item.Image
+			if attrValue.Image != "" {
+				row.Image = attrValue.Image
+			}
+			row.Image = utils.GetImagePath(row.Image)
+			row.Suk = attrValue.Suk
+			row.Price = attrValue.Price
+			row.Stock = attrValue.Stock
+			row.IsValid = attrValue.Stock >= cart.Num
+		}
+		list = append(list, row)
+	}
+
+	return list, nil
+}
+
+// 加入购物车，已存在的规格合并数量
+func (p *CartService) Add(uid int, param request.CartAddReq) error {
+	if param.Num <= 0 {
+		return errors.New("购买数量必须大于0")
+	}
+
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		_, attrValue, err := p.getSku(tx, param.ItemId, param.AttrValueId)
+		if err != nil {
+			return err
+		}
+
+		cart := model.Cart{}
+		tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ?", uid).
+			Where("attr_value_id = ?", param.AttrValueId).
+			Limit(1).
+			Find(&cart)
+		if cart.Id > 0 {
+			if cart.Num+param.Num > attrValue.Stock {
+				return errors.New("库存不足")
+			}
+			return tx.Model(&model.Cart{}).Where("id = ?", cart.Id).Update("num", cart.Num+param.Num).Error
+		}
+
+		if param.Num > attrValue.Stock {
+			return errors.New("库存不足")
+		}
+		var count int64
+		if err := tx.Model(&model.Cart{}).Where("uid = ?", uid).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= CartLimit {
+			return errors.New("购物车已满，请先清理")
+		}
+
+		return tx.Create(&model.Cart{
+			Uid:         uid,
+			ItemId:      param.ItemId,
+			AttrValueId: param.AttrValueId,
+			Num:         param.Num,
+		}).Error
+	})
+}
+
+// 修改购物车数量
+func (p *CartService) Update(uid int, param request.CartUpdateReq) error {
+	if param.Num <= 0 {
+		return errors.New("购买数量必须大于0")
+	}
+
+	cart := model.Cart{}
+	if err := db.Client.Where("id = ?", param.Id).Where("uid = ?", uid).First(&cart).Error; err != nil {
+		return errors.New("购物车商品不存在")
+	}
+	_, attrValue, err := p.getSku(db.Client, cart.ItemId, cart.AttrValueId)
+	if err != nil {
+		return err
+	}
+	if param.Num > attrValue.Stock {
+		return errors.New("库存不足")
+	}
+
+	return db.Client.Model(&model.Cart{}).Where("id = ?", cart.Id).Update("num", param.Num).Error
+}
+
+// 删除购物车商品
+func (p *CartService) Delete(uid int, ids []int) error {
+	if len(ids) == 0 {
+		return errors.New("请选择要删除的商品")
+	}
+	return db.Client.Where("uid = ?", uid).Where("id IN ?", ids).Delete(&model.Cart{}).Error
+}
+
+// 购物车结算，下单成功后删除已结算的商品
+func (p *CartService) Checkout(uid int, param request.CartCheckoutReq) (order model.Order, err error) {
+	if len(param.Ids) == 0 {
+		return order, errors.New("请选择要结算的商品")
+	}
+
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		carts := []model.Cart{}
+		if err := tx.Where("uid = ?", uid).Where("id IN ?", param.Ids).Order("id asc").Find(&carts).Error; err != nil {
+			return err
+		}
+		if len(carts) != len(param.Ids) {
+			return errors.New("购物车商品不存在，请刷新后重试")
+		}
+
+		details := []request.OrderDetail{}
+		for _, cart := range carts {
+			details = append(details, request.OrderDetail{
+				ItemId:      cart.ItemId,
+				AttrValueId: cart.AttrValueId,
+				PayNum:      cart.Num,
+			})
+		}
+
+		order, err = NewOrderService().SubmitWithTx(tx, uid, request.SubmitOrderReq{
+			ShippingType: param.ShippingType,
+			AddressId:    param.AddressId,
+			CouponId:     param.CouponId,
+			Realname:     param.Realname,
+			UserPhone:    param.UserPhone,
+			UserAddress:  param.UserAddress,
+			OrderDetails: details,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("uid = ?", uid).Where("id IN ?", param.Ids).Delete(&model.Cart{}).Error
+	})
+
+	return order, err
+}
+
+// 获取可购买的商品规格
+func (p *CartService) getSku(tx *gorm.DB, itemId int, attrValueId int) (item model.Item, attrValue model.ItemAttrValue, err error) {
+	err = tx.Where("id = ?", attrValueId).Where("item_id = ?", itemId).Where("status = ?", 1).First(&attrValue).Error
+	if err != nil {
+		return item, attrValue, errors.New("商品规格不存在")
+	}
+	err = tx.Where("id = ?", itemId).Where("status = ?", 1).First(&item).Error
+	if err != nil {
+		return item, attrValue, errors.New("商品不存在或已下架")
+	}
+	return item, attrValue, nil
+}
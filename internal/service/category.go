@@ -1,8 +1,12 @@
 package service
 
 import (
+	"strconv"
+
 	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
 	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
 )
 
 type CategoryService struct{}
@@ -32,3 +36,72 @@ func (p *CategoryService) GetListWithRoot(categoryType string) (categories []mod
 	list = append(list, model.Category{Id: 0, Pid: -1, Title: "根节点"})
 	return list, err
 }
+
+// 获取分类树
+func (p *CategoryService) GetTree(categoryType string) (list []response.CategoryTreeResp, err error) {
+	categories := []response.CategoryTreeResp{}
+	err = db.Client.
+		Model(model.Category{}).
+		Where("status = ?", 1).
+		Where("type = ?", categoryType).
+		Order("sort asc, id asc").
+		Select("id", "pid", "title", "name", "description", "cover_id").
+		Find(&categories).Error
+	if err != nil {
+		return list, err
+	}
+	for index, category := range categories {
+		categories[index].CoverId = utils.GetImagePath(category.CoverId)
+	}
+	return p.buildTree(categories, 0), nil
+}
+
+// 递归组装分类树
+func (p *CategoryService) buildTree(categories []response.CategoryTreeResp, pid int) (list []response.CategoryTreeResp) {
+	list = make([]response.CategoryTreeResp, 0)
+	for _, category := range categories {
+		if category.Pid != pid {
+			continue
+		}
+		category.Children = p.buildTree(categories, category.Id)
+		list = append(list, category)
+	}
+	return list
+}
+
+// 获取分类及其全部子分类的ID
+func (p *CategoryService) GetDescendantIds(id int, categoryType string) []int {
+	categories, _ := p.GetList(categoryType)
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range categories {
+			if category.Pid == ids[i] {
+				ids = append(ids, category.Id)
+			}
+		}
+	}
+	return ids
+}
+
+// 通过ID或缩略名获取分类
+func (p *CategoryService) GetInfoByKey(key string, categoryType string) (category model.Category, err error) {
+	query := db.Client.Where("status = ?", 1).Where("type = ?", categoryType)
+	if id, convErr := strconv.Atoi(key); convErr == nil {
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("name = ?", key)
+	}
+	err = query.First(&category).Error
+	return category, err
+}
+
+// 获取子分类
+func (p *CategoryService) GetChildren(pid int, categoryType string) (categories []model.Category, err error) {
+	err = db.Client.
+		Where("status = ?", 1).
+		Where("type = ?", categoryType).
+		Where("pid = ?", pid).
+		Order("sort asc, id asc").
+		Find(&categories).Error
+	return categories, err
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 核销二维码内容前缀，扫码结果去掉前缀即为核销码
+const VerifyQrcodePrefix = "verify:"
+
+type ClerkService struct{}
+
+func NewClerkService() *ClerkService {
+	return &ClerkService{}
+}
+
+// 获取用户绑定的核销员
+func (p *ClerkService) GetInfoByUid(uid int) (clerk model.Clerk, err error) {
+	err = db.Client.Where("uid = ?", uid).Where("status = ?", 1).First(&clerk).Error
+	if err != nil {
+		return clerk, errors.New("您不是核销员，无权核销订单")
+	}
+	return clerk, nil
+}
+
+// 获取订单核销二维码内容
+func (p *ClerkService) GetVerifyQrcode(verifyCode string) string {
+	return VerifyQrcodePrefix + verifyCode
+}
+
+// 核销订单，同一订单只能核销一次
+//
+// code 可以是核销码，也可以是扫描核销二维码得到的内容
+func (p *ClerkService) Verify(clerk model.Clerk, code string) (order model.Order, err error) {
+	verifyCode := strings.TrimPrefix(strings.TrimSpace(code), VerifyQrcodePrefix)
+	if verifyCode == "" {
+		return order, errors.New("核销码不能为空")
+	}
+
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("verify_code = ?", verifyCode).First(&order).Error
+		if err != nil {
+			return errors.New("核销码无效")
+		}
+		if order.MerchantId != clerk.MerchantId {
+			return errors.New("不能核销其他商户的订单")
+		}
+		if order.IsMerchantCheck == 1 {
+			return errors.New("订单已核销，请勿重复操作")
+		}
+
+		order, err = NewOrderService().TransitionWithTx(tx, dto.OrderTransitionDTO{
+			OrderId:   order.Id,
+			Event:     model.OrderEventVerify,
+			ActorType: model.OrderActorClerk,
+			ActorId:   clerk.Id,
+			Reason:    "核销员" + clerk.Name + "核销",
+			Data: map[string]interface{}{
+				"clerk_id": clerk.Id,
+			},
+		})
+		return err
+	})
+	return order, err
+}
+
+// 生成核销码
+func makeVerifyCode(tx *gorm.DB) (string, error) {
+	for i := 0; i < 5; i++ {
+		verifyCode := rand.MakeNumeric(12)
+		var count int64
+		if err := tx.Model(&model.Order{}).Unscoped().Where("verify_code = ?", verifyCode).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return verifyCode, nil
+		}
+	}
+	return "", errors.New("生成核销码失败，请重试")
+}
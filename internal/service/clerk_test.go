@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+func TestClerkVerify(t *testing.T) {
+	setupDB(t, &model.Order{}, &model.OrderLog{})
+	clerk := model.Clerk{Id: 1, Name: "店员", MerchantId: 1}
+
+	// 自提订单支付后无需备货即可核销
+	order := model.Order{OrderNo: "V001", MerchantId: 1, Paid: 1, ShippingType: model.ShippingTypePickup, Status: model.OrderStatusPaid, VerifyCode: "100000000001"}
+	if err := db.Client.Create(&order).Error; err != nil {
+		t.Fatal(err)
+	}
+	order, err := NewClerkService().Verify(clerk, VerifyQrcodePrefix+"100000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != model.OrderStatusCompleted || order.IsMerchantCheck != 1 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+	if _, err = NewClerkService().Verify(clerk, "100000000001"); err == nil {
+		t.Fatal("expected verifying twice to fail")
+	}
+
+	// 其他商户和快递订单不能核销
+	other := model.Order{OrderNo: "V002", MerchantId: 2, Paid: 1, ShippingType: model.ShippingTypePickup, Status: model.OrderStatusPaid, VerifyCode: "100000000002"}
+	express := model.Order{OrderNo: "V003", MerchantId: 1, Paid: 1, ShippingType: model.ShippingTypeExpress, Status: model.OrderStatusPaid, VerifyCode: "100000000003"}
+	if err = db.Client.Create([]*model.Order{&other, &express}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"100000000002", "100000000003"} {
+		if _, err = NewClerkService().Verify(clerk, code); err == nil {
+			t.Fatalf("expected verifying %s to fail", code)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponService struct{}
+
+func NewCouponService() *CouponService {
+	return &CouponService{}
+}
+
+// 获取可领取的优惠券列表
+func (p *CouponService) GetClaimableList(uid int) (list []response.CouponResp, err error) {
+	list = make([]response.CouponResp, 0)
+	now := time.Now()
+
+	coupons := []model.Coupon{}
+	err = db.Client.
+		Where("status = ?", 1).
+		Where("is_public = ?", 1).
+		Where("claim_start_time IS NULL OR claim_start_time <= ?", now).
+		Where("claim_end_time IS NULL OR claim_end_time > ?", now).
+		Where("total = 0 OR issued < total").
+		Order("id desc").
+		Find(&coupons).Error
+	if err != nil {
+		return list, err
+	}
+
+	for _, coupon := range coupons {
+		var received int64
+		db.Client.Model(&model.UserCoupon{}).Where("uid = ?", uid).Where("coupon_id = ?", coupon.Id).Count(&received)
+		list = append(list, response.CouponResp{
+			Id:          coupon.Id,
+			Title:       coupon.Title,
+			Type:        uint8(coupon.Type),
+			Amount:      coupon.Amount,
+			Rate:        coupon.Rate,
+			MinPrice:    coupon.MinPrice,
+			MaxDiscount: coupon.MaxDiscount,
+			ValidDays:   coupon.ValidDays,
+			CanReceive:  coupon.PerLimit <= 0 || int(received) < coupon.PerLimit,
+		})
+	}
+
+	return list, nil
+}
+
+// 获取用户的优惠券列表，status 为 unused、used、expired，为空时查询全部
+func (p *CouponService) GetUserList(uid int, status string) (list []model.UserCoupon, err error) {
+	list = make([]model.UserCoupon, 0)
+	now := time.Now()
+	query := db.Client.Where("uid = ?", uid)
+
+	switch status {
+	case "unused":
+		query = query.Where("status = ?", model.UserCouponStatusUnused).Where("end_time > ?", now)
+	case "used":
+		query = query.Where("status = ?", model.UserCouponStatusUsed)
+	case "expired":
+		query = query.Where("status = ?", model.UserCouponStatusUnused).Where("end_time <= ?", now)
+	}
+
+	err = query.Order("id desc").Find(&list).Error
+	return list, err
+}
+
+// 用户领取优惠券
+func (p *CouponService) Receive(uid int, couponId int) (userCoupon model.UserCoupon, err error) {
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		coupon := model.Coupon{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", couponId).
+			Where("status = ?", 1).
+			Where("is_public = ?", 1).
+			First(&coupon).Error
+		if err != nil {
+			return errors.New("优惠券不存在")
+		}
+
+		now := time.Now()
+		if !coupon.ClaimStartTime.IsZero() && coupon.ClaimStartTime.After(now) {
+			return errors.New("优惠券领取未开始")
+		}
+		if !coupon.ClaimEndTime.IsZero() && !coupon.ClaimEndTime.After(now) {
+			return errors.New("优惠券领取已结束")
+		}
+
+		userCoupon, err = p.issueWithTx(tx, coupon, uid)
+		return err
+	})
+	return userCoupon, err
+}
+
+// 后台发放优惠券，不受领取时间和公开状态限制
+func (p *CouponService) Issue(couponId int, uid int) (userCoupon model.UserCoupon, err error) {
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		coupon := model.Coupon{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", couponId).
+			Where("status = ?", 1).
+			First(&coupon).Error
+		if err != nil {
+			return errors.New("优惠券不存在或已禁用")
+		}
+
+		if _, err := NewUserService().GetInfoById(uid); err != nil {
+			return errors.New("用户不存在")
+		}
+
+		userCoupon, err = p.issueWithTx(tx, coupon, uid)
+		return err
+	})
+	return userCoupon, err
+}
+
+// 发放优惠券，调用前需锁定优惠券模板
+func (p *CouponService) issueWithTx(tx *gorm.DB, coupon model.Coupon, uid int) (userCoupon model.UserCoupon, err error) {
+	if coupon.Total > 0 && coupon.Issued >= coupon.Total {
+		return userCoupon, errors.New("优惠券已领完")
+	}
+
+	if coupon.PerLimit > 0 {
+		var received int64
+		err = tx.Model(&model.UserCoupon{}).Where("uid = ?", uid).Where("coupon_id = ?", coupon.Id).Count(&received).Error
+		if err != nil {
+			return userCoupon, err
+		}
+		if int(received) >= coupon.PerLimit {
+			return userCoupon, errors.New("已达到领取上限")
+		}
+	}
+
+	now := time.Now()
+	userCoupon = model.UserCoupon{
+		Uid:         uid,
+		CouponId:    coupon.Id,
+		Title:       coupon.Title,
+		Type:        coupon.Type,
+		Amount:      coupon.Amount,
+		Rate:        coupon.Rate,
+		MinPrice:    coupon.MinPrice,
+		MaxDiscount: coupon.MaxDiscount,
+		CategoryIds: coupon.CategoryIds,
+		StartTime:   datetime.Datetime{Time: now},
+		EndTime:     datetime.Datetime{Time: now.AddDate(0, 0, coupon.ValidDays)},
+		Status:      model.UserCouponStatusUnused,
+	}
+	if err = tx.Create(&userCoupon).Error; err != nil {
+		return userCoupon, err
+	}
+
+	err = tx.Model(&model.Coupon{}).Where("id = ?", coupon.Id).Update("issued", gorm.Expr("issued + ?", 1)).Error
+	return userCoupon, err
+}
+
+// 锁定并校验用户优惠券
+func (p *CouponService) getUsableWithTx(tx *gorm.DB, uid int, userCouponId int) (userCoupon model.UserCoupon, err error) {
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userCouponId).
+		Where("uid = ?", uid).
+		First(&userCoupon).Error
+	if err != nil {
+		return userCoupon, errors.New("优惠券不存在")
+	}
+	if userCoupon.Status != model.UserCouponStatusUnused {
+		return userCoupon, errors.New("优惠券已使用")
+	}
+
+	now := time.Now()
+	if userCoupon.StartTime.After(now) || !userCoupon.EndTime.After(now) {
+		return userCoupon, errors.New("优惠券不在有效期内")
+	}
+	return userCoupon, nil
+}
+
+// 计算优惠券可抵扣的金额，lines 为订单商品行
+func (p *CouponService) Calculate(userCoupon model.UserCoupon, lines []orderPriceLine) (float64, error) {
+	categoryIds := parseCategoryIds(userCoupon.CategoryIds)
+
+	// 限定分类的优惠券只计算分类内商品的金额
+	eligible := 0.0
+	for _, line := range lines {
+		if len(categoryIds) == 0 || line.inCategories(categoryIds) {
+			eligible += line.Total
+		}
+	}
+	eligible = roundPrice(eligible)
+	if eligible <= 0 {
+		return 0, errors.New("订单中没有可使用该优惠券的商品")
+	}
+	if eligible < userCoupon.MinPrice {
+		return 0, errors.New("订单金额未达到优惠券使用门槛")
+	}
+
+	discount := 0.0
+	switch userCoupon.Type {
+	case model.CouponTypeFixed:
+		discount = userCoupon.Amount
+	case model.CouponTypePercent:
+		discount = eligible * float64(100-userCoupon.Rate) / 100
+		if userCoupon.MaxDiscount > 0 {
+			discount = math.Min(discount, userCoupon.MaxDiscount)
+		}
+	default:
+		return 0, errors.New("未知的优惠券类型")
+	}
+
+	return roundPrice(math.Min(discount, eligible)), nil
+}
+
+// 使用优惠券
+func (p *CouponService) UseWithTx(tx *gorm.DB, userCouponId int, orderId int) error {
+	result := tx.Model(&model.UserCoupon{}).
+		Where("id = ?", userCouponId).
+		Where("status = ?", model.UserCouponStatusUnused).
+		Updates(map[string]interface{}{
+			"status":    model.UserCouponStatusUsed,
+			"order_id":  orderId,
+			"used_time": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("优惠券已使用")
+	}
+	return nil
+}
+
+// 退回订单使用的优惠券
+func (p *CouponService) ReleaseWithTx(tx *gorm.DB, orderId int) error {
+	return tx.Model(&model.UserCoupon{}).
+		Where("order_id = ?", orderId).
+		Where("status = ?", model.UserCouponStatusUsed).
+		Updates(map[string]interface{}{
+			"status":    model.UserCouponStatusUnused,
+			"order_id":  0,
+			"used_time": nil,
+		}).Error
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// 单规格商品的默认规格索引值
+const ItemDefaultSuk = "默认"
+
+// 商品列表支持的排序字段
+var itemOrderByColumns = map[string]string{
+	"sort":  "sort",
+	"price": "price",
+	"sales": "sales",
+}
+
+type ItemService struct{}
+
+func NewItemService() *ItemService {
+	return &ItemService{}
+}
+
+// 获取商品分页列表
+func (p *ItemService) GetPage(param request.ItemIndexQueryReq) (list []response.ItemIndexResp, total int64, err error) {
+	list = make([]response.ItemIndexResp, 0)
+	query := db.Client.Model(model.Item{}).Where("status = ?", 1)
+
+	// 分类筛选
+	if param.CategoryId > 0 {
+		query = query.Where("JSON_CONTAINS(category_ids, ?)", strconv.Itoa(param.CategoryId))
+	}
+
+	// 商户筛选
+	if param.MerchantId != nil {
+		query = query.Where("merchant_id = ?", *param.MerchantId)
+	}
+
+	// 名称、关键字模糊搜索
+	if param.ItemNameKeyword != "" {
+		keyword := "%" + param.ItemNameKeyword + "%"
+		query = query.Where("name LIKE ? OR keyword LIKE ?", keyword, keyword)
+	}
+
+	if err = query.Count(&total).Error; err != nil {
+		return list, total, err
+	}
+
+	// 排序字段只允许白名单内的字段，防止注入
+	column, ok := itemOrderByColumns[param.OrderByColumn]
+	if !ok {
+		column = "sort"
+	}
+	direction := "asc"
+	if param.IsAsc != nil && !*param.IsAsc {
+		direction = "desc"
+	}
+
+	page, pageSize := param.Page, param.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	err = query.
+		Order(column+" "+direction+", id desc").
+		Offset((page-1)*pageSize).
+		Limit(pageSize).
+		Select("id", "name", "image", "price", "sales + ficti_sales AS ficti_sales").
+		Find(&list).Error
+	return list, total, err
+}
+
+// 通过ID获取商品
+func (p *ItemService) GetInfoById(id int) (item model.Item, err error) {
+	err = db.Client.Where("id = ?", id).First(&item).Error
+	return item, err
+}
+
+// 通过ID获取商品规格属性值
+func (p *ItemService) GetAttrValueById(id int) (attrValue model.ItemAttrValue, err error) {
+	err = db.Client.Where("id = ?", id).First(&attrValue).Error
+	return attrValue, err
+}
+
+// 获取商品规格
+func (p *ItemService) GetAttrs(itemId int) (attrs []dto.AttrDTO, err error) {
+	list := []model.ItemAttr{}
+	err = db.Client.Where("item_id = ?", itemId).Order("id asc").Find(&list).Error
+	if err != nil {
+		return attrs, err
+	}
+	for _, v := range list {
+		attrs = append(attrs, dto.AttrDTO{
+			Id:         v.Id,
+			ItemId:     v.ItemId,
+			AttrName:   v.AttrName,
+			AttrValues: v.AttrValues,
+			AttrItems:  strings.Split(v.AttrValues, ","),
+		})
+	}
+	return attrs, nil
+}
+
+// 获取商品规格属性值
+func (p *ItemService) GetAttrValues(itemId int, onlyEnabled bool) (attrValues []dto.AttrValueDTO, err error) {
+	list := []model.ItemAttrValue{}
+	query := db.Client.Where("item_id = ?", itemId)
+	if onlyEnabled {
+		query = query.Where("status = ?", 1)
+	}
+	if err = query.Order("id asc").Find(&list).Error; err != nil {
+		return attrValues, err
+	}
+	for _, v := range list {
+		attrValues = append(attrValues, p.attrValueToDTO(v))
+	}
+	return attrValues, nil
+}
+
+// 获取商品详情，用于前台展示
+func (p *ItemService) GetDetail(id int) (item dto.ItemDTO, err error) {
+	info := model.Item{}
+	err = db.Client.
+		Where("id = ?", id).
+		Where("status = ?", 1).
+		First(&info).Error
+	if err != nil {
+		return item, errors.New("商品不存在或已下架")
+	}
+
+	// 增加浏览量
+	db.Client.Model(model.Item{}).Where("id = ?", id).UpdateColumn("views", gorm.Expr("views + ?", 1))
+
+	// 轮播图转换为图片地址的Json数组
+	sliderImage := "[]"
+	if info.SliderImage != "" {
+		if paths := utils.GetImagePaths(info.SliderImage); len(paths) > 0 {
+			sliderImageBytes, _ := json.Marshal(paths)
+			sliderImage = string(sliderImageBytes)
+		}
+	}
+
+	item = dto.ItemDTO{
+		Id:          info.Id,
+		MerchantId:  info.MerchantId,
+		Image:       utils.GetImagePath(info.Image),
+		SliderImage: sliderImage,
+		Name:        info.Name,
+		Keyword:     info.Keyword,
+		Description: info.Description,
+		Content:     utils.ReplaceContentSrc(info.Content),
+		CategoryIds: info.CategoryIds,
+		Price:       info.Price,
+		OtPrice:     info.OtPrice,
+		Sort:        info.Sort,
+		Sales:       info.Sales + info.FictiSales,
+		Stock:       info.Stock,
+		Status:      info.Status,
+		FictiSales:  info.FictiSales,
+		Views:       info.Views + info.FictiViews + 1,
+		FictiViews:  info.FictiViews,
+		SpecType:    info.SpecType,
+	}
+	if item.Attrs, err = p.GetAttrs(info.Id); err != nil {
+		return item, err
+	}
+	if item.AttrValues, err = p.GetAttrValues(info.Id, true); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// 获取商品分类树
+func (p *ItemService) GetCategoryTree() (list []response.ItemCategoryResp, err error) {
+	categories := []response.ItemCategoryResp{}
+	err = db.Client.
+		Model(model.Category{}).
+		Where("status = ?", 1).
+		Where("type = ?", "ITEM").
+		Order("sort asc, id asc").
+		Select("id", "pid", "title", "cover_id").
+		Find(&categories).Error
+	if err != nil {
+		return list, err
+	}
+	for index, category := range categories {
+		categories[index].CoverId = utils.GetImagePath(category.CoverId)
+	}
+	return p.buildCategoryTree(categories, 0), nil
+}
+
+// 递归组装分类树
+func (p *ItemService) buildCategoryTree(categories []response.ItemCategoryResp, pid int) (list []response.ItemCategoryResp) {
+	list = make([]response.ItemCategoryResp, 0)
+	for _, category := range categories {
+		if category.Pid != pid {
+			continue
+		}
+		category.Children = p.buildCategoryTree(categories, category.Id)
+		list = append(list, category)
+	}
+	return list
+}
+
+// 保存商品规格，单规格商品也会生成一条默认规格属性值，便于下单时统一处理库存
+func (p *ItemService) SaveSpecs(itemId int, specType uint8, attrs []dto.ItemAttrDTO, attrValues []dto.AttrValueDTO) error {
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("item_id = ?", itemId).Delete(&model.ItemAttr{}).Error; err != nil {
+			return err
+		}
+		if specType == 1 {
+			for _, attr := range attrs {
+				values := []string{}
+				for _, item := range attr.Items {
+					values = append(values, item.Name)
+				}
+				if err := tx.Create(&model.ItemAttr{
+					ItemId:     itemId,
+					AttrName:   attr.Name,
+					AttrValues: strings.Join(values, ","),
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		// 已存在的规格按索引值更新，保证订单、购物车中引用的规格ID不变
+		existed := []model.ItemAttrValue{}
+		if err := tx.Where("item_id = ?", itemId).Find(&existed).Error; err != nil {
+			return err
+		}
+		existedIds := map[string]int{}
+		for _, v := range existed {
+			existedIds[v.Suk] = v.Id
+		}
+
+		keepIds := []int{}
+		for _, attrValue := range attrValues {
+			if specType != 1 {
+				attrValue.Suk = ItemDefaultSuk
+				attrValue.IsDefault = true
+				attrValue.Status = true
+			}
+			image, _ := json.Marshal(attrValue.Image)
+			value, _ := json.Marshal(attrValue.AttrValue)
+			data := model.ItemAttrValue{
+				ItemId:    itemId,
+				Suk:       attrValue.Suk,
+				Stock:     attrValue.Stock,
+				Price:     attrValue.Price,
+				Image:     string(image),
+				Cost:      attrValue.Cost,
+				OtPrice:   attrValue.OtPrice,
+				AttrValue: string(value),
+				IsDefault: boolToUint8(attrValue.IsDefault),
+				Status:    boolToUint8(attrValue.Status),
+			}
+			if id, ok := existedIds[attrValue.Suk]; ok {
+				err := tx.Model(&model.ItemAttrValue{}).Where("id = ?", id).Select(
+					"stock", "price", "image", "cost", "ot_price", "attr_value", "is_default", "status",
+				).Updates(&data).Error
+				if err != nil {
+					return err
+				}
+				keepIds = append(keepIds, id)
+				continue
+			}
+			if err := tx.Create(&data).Error; err != nil {
+				return err
+			}
+			keepIds = append(keepIds, data.Id)
+		}
+
+		deleteQuery := tx.Where("item_id = ?", itemId)
+		if len(keepIds) > 0 {
+			deleteQuery = deleteQuery.Where("id NOT IN ?", keepIds)
+		}
+		if err := deleteQuery.Delete(&model.ItemAttrValue{}).Error; err != nil {
+			return err
+		}
+
+		return p.syncPriceAndStock(tx, itemId)
+	})
+}
+
+// 根据规格属性值同步商品的价格与总库存
+func (p *ItemService) syncPriceAndStock(tx *gorm.DB, itemId int) error {
+	var result struct {
+		Price   float64
+		OtPrice float64
+		Cost    float64
+		Stock   int
+	}
+	err := tx.Model(&model.ItemAttrValue{}).
+		Where("item_id = ?", itemId).
+		Where("status = ?", 1).
+		Select("IFNULL(MIN(price), 0) AS price, IFNULL(MIN(ot_price), 0) AS ot_price, IFNULL(MIN(cost), 0) AS cost, IFNULL(SUM(stock), 0) AS stock").
+		Scan(&result).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&model.Item{}).Where("id = ?", itemId).Updates(map[string]interface{}{
+		"price":    result.Price,
+		"ot_price": result.OtPrice,
+		"cost":     result.Cost,
+		"stock":    result.Stock,
+	}).Error
+}
+
+// 规格属性值转换为DTO
+func (p *ItemService) attrValueToDTO(v model.ItemAttrValue) dto.AttrValueDTO {
+	var image, attrValue interface{}
+	json.Unmarshal([]byte(v.Image), &image)
+	json.Unmarshal([]byte(v.AttrValue), &attrValue)
+	return dto.AttrValueDTO{
+		Id:        v.Id,
+		ItemId:    v.ItemId,
+		Suk:       v.Suk,
+		Stock:     v.Stock,
+		Sales:     v.Sales,
+		Price:     v.Price,
+		Image:     image,
+		ImageJson: v.Image,
+		Cost:      v.Cost,
+		OtPrice:   v.OtPrice,
+		AttrValue: attrValue,
+		IsDefault: v.IsDefault == 1,
+		Status:    v.Status == 1,
+	}
+}
+
+// 布尔值转换为数据库存储的状态值
+func boolToUint8(value bool) uint8 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/scheduler"
+	"gorm.io/gorm/clause"
+)
+
+type JobService struct{}
+
+func NewJobService() *JobService {
+	return &JobService{}
+}
+
+// 同步已注册的任务定义到数据表，保留任务的启用状态和执行情况
+func (p *JobService) Sync(jobs []scheduler.Job) error {
+	for _, job := range jobs {
+		var singleton uint8
+		if job.Singleton {
+			singleton = 1
+		}
+		err := db.Client.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "spec", "timeout", "singleton", "updated_at"}),
+		}).Create(&model.Job{
+			Name:      job.Name,
+			Title:     job.Title,
+			Spec:      job.Spec,
+			Timeout:   int(job.Timeout.Seconds()),
+			Singleton: singleton,
+			Status:    1,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 通过ID获取任务
+func (p *JobService) GetInfoById(id interface{}) (job model.Job, err error) {
+	err = db.Client.Where("id = ?", id).First(&job).Error
+	return job, err
+}
+
+// 任务是否启用，未同步到数据表的任务默认启用
+func (p *JobService) IsEnabled(name string) bool {
+	job := model.Job{}
+	if err := db.Client.Where("name = ?", name).First(&job).Error; err != nil {
+		return true
+	}
+	return job.Status == 1
+}
+
+// 保存任务执行记录
+func (p *JobService) Record(run scheduler.Run) error {
+	status := model.JobRunStatusSuccess
+	message := ""
+	if run.Err != nil {
+		status = model.JobRunStatusFailed
+		message = run.Err.Error()
+	}
+
+	err := db.Client.Create(&model.JobRun{
+		JobName:     run.Name,
+		TriggerType: run.Trigger,
+		Status:      status,
+		StartedAt:   datetime.Datetime{Time: run.StartedAt},
+		Duration:    int(run.Duration.Milliseconds()),
+		Error:       message,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Client.Model(&model.Job{}).Where("name = ?", run.Name).Updates(map[string]interface{}{
+		"last_run_at":   run.StartedAt,
+		"last_status":   status,
+		"last_duration": run.Duration.Milliseconds(),
+		"last_error":    message,
+	}).Error
+}
+
+// 清理指定时间之前的执行记录
+func (p *JobService) PruneRuns(ctx context.Context, before time.Time) error {
+	return db.Client.WithContext(ctx).Where("created_at < ?", before).Delete(&model.JobRun{}).Error
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/logistics"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+type LogisticsService struct{}
+
+func NewLogisticsService() *LogisticsService {
+	return &LogisticsService{}
+}
+
+// 获取当前配置的物流查询服务，默认使用本地模拟服务
+func (p *LogisticsService) GetTracker() (logistics.Tracker, error) {
+	name := utils.GetConfig("LOGISTICS_TRACKER")
+	if name == "" {
+		name = logistics.TrackerFake
+	}
+	return logistics.GetTracker(name)
+}
+
+// 快递公司选项
+func (p *LogisticsService) CarrierOptions() (options []selectfield.Option) {
+	for _, carrier := range logistics.Carriers {
+		options = append(options, selectfield.Option{Label: carrier.Name, Value: carrier.Code})
+	}
+	return options
+}
+
+// 查询订单物流轨迹
+func (p *LogisticsService) TrackOrder(order model.Order) (*logistics.TrackResult, error) {
+	if order.ShippingType != model.ShippingTypeExpress || order.DeliveryNo == "" {
+		return nil, errors.New("订单未发货")
+	}
+
+	tracker, err := p.GetTracker()
+	if err != nil {
+		return nil, err
+	}
+	return tracker.Track(context.Background(), logistics.TrackRequest{
+		CarrierCode: order.DeliveryCode,
+		TrackingNo:  order.DeliveryNo,
+		Phone:       order.UserPhone,
+	})
+}
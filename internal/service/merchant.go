@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+type MerchantService struct{}
+
+func NewMerchantService() *MerchantService {
+	return &MerchantService{}
+}
+
+// 获取管理员所属的商户id，平台管理员返回0
+//
+// 管理员关联的商户已禁用或已删除时返回错误，不能按平台管理员处理
+func (p *MerchantService) GetMerchantIdByAdminId(adminId int) (merchantId int, isMerchantAdmin bool, err error) {
+	merchant := model.Merchant{}
+	err = db.Client.
+		Where("admin_id = ?", adminId).
+		Where("status = ?", 1).
+		Limit(1).
+		Find(&merchant).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if merchant.Id > 0 {
+		return merchant.Id, true, nil
+	}
+
+	err = db.Client.Unscoped().
+		Where("admin_id = ?", adminId).
+		Limit(1).
+		Find(&merchant).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if merchant.Id > 0 {
+		return merchant.Id, true, errors.New("所属商户已禁用或已删除")
+	}
+	return 0, false, nil
+}
+
+// 商户选项，包含平台自营
+func (p *MerchantService) Options() []selectfield.Option {
+	merchants := []model.Merchant{}
+	db.Client.Where("status = ?", 1).Order("id asc").Find(&merchants)
+
+	options := []selectfield.Option{
+		{Label: "平台自营", Value: 0},
+	}
+	for _, merchant := range merchants {
+		options = append(options, selectfield.Option{Label: merchant.Name, Value: merchant.Id})
+	}
+	return options
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// 默认订单支付超时时间，超时未支付的订单会被自动取消
+const OrderPayTimeout = 30 * time.Minute
+
+type OrderService struct{}
+
+func NewOrderService() *OrderService {
+	return &OrderService{}
+}
+
+// 获取订单支付超时时间，可通过配置项 ORDER_PAY_TIMEOUT 设置，单位分钟
+func (p *OrderService) GetPayTimeout() time.Duration {
+	minutes, err := strconv.Atoi(utils.GetConfig("ORDER_PAY_TIMEOUT"))
+	if err != nil || minutes <= 0 {
+		return OrderPayTimeout
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// 订单是否已支付超时
+func (p *OrderService) IsPayExpired(order model.Order) bool {
+	return time.Since(order.CreatedAt.Time) > p.GetPayTimeout()
+}
+
+// 通过订单号获取订单
+func (p *OrderService) GetInfoByOrderNo(orderNo string) (order model.Order, err error) {
+	err = db.Client.Where("order_no = ?", orderNo).First(&order).Error
+	return order, err
+}
+
+// 获取订单信息
+func (p *OrderService) GetInfoById(id interface{}) (order model.Order, err error) {
+	err = db.Client.Where("id = ?", id).First(&order).Error
+	return order, err
+}
+
+// 获取用户的订单
+func (p *OrderService) GetUserOrderByOrderNo(uid int, orderNo string) (order model.Order, err error) {
+	err = db.Client.
+		Where("uid = ?", uid).
+		Where("order_no = ?", orderNo).
+		First(&order).Error
+	if err != nil {
+		return order, errors.New("订单不存在")
+	}
+	return order, nil
+}
+
+// 获取订单详情
+func (p *OrderService) GetDetails(orderId int) (details []model.OrderDetail, err error) {
+	err = db.Client.Where("order_id = ?", orderId).Order("id asc").Find(&details).Error
+	return details, err
+}
+
+// 获取用户的订单详情
+func (p *OrderService) GetUserOrderDetail(uid int, orderNo string) (info dto.OrderDTO, err error) {
+	order, err := p.GetUserOrderByOrderNo(uid, orderNo)
+	if err != nil {
+		return info, err
+	}
+
+	info = dto.OrderDTO{
+		Id:                    order.Id,
+		OrderNo:               order.OrderNo,
+		Uid:                   order.Uid,
+		Realname:              order.Realname,
+		UserPhone:             order.UserPhone,
+		UserAddress:           order.UserAddress,
+		TotalNum:              order.TotalNum,
+		TotalPrice:            order.TotalPrice,
+		PayPrice:              order.PayPrice,
+		CouponId:              order.CouponId,
+		CouponPrice:           order.CouponPrice,
+		Discounts:             []dto.OrderDiscountDTO{},
+		Paid:                  order.Paid,
+		PayTime:               order.PayTime,
+		PayType:               order.PayType,
+		OrderDetails:          []dto.OrderDetailDTO{},
+		Status:                uint8(order.Status),
+		RefundStatus:          uint8(order.RefundStatus),
+		RefundReasonImg:       order.RefundReasonImg,
+		RefundReasonExplain:   order.RefundReasonExplain,
+		RefundReason:          order.RefundReason,
+		RefundRejectionReason: order.RefundRejectionReason,
+		RefundReasonTime:      order.RefundReasonTime,
+		RefundPrice:           order.RefundPrice,
+		RefundedPrice:         order.RefundedPrice,
+		MerchantId:            order.MerchantId,
+		IsMerchantCheck:       order.IsMerchantCheck,
+		VerifyCode:            order.VerifyCode,
+		ShippingType:          uint8(order.ShippingType),
+		DeliveryCode:          order.DeliveryCode,
+		DeliveryName:          order.DeliveryName,
+		DeliveryNo:            order.DeliveryNo,
+		DeliveryTime:          order.DeliveryTime,
+		ClerkId:               order.ClerkId,
+		CreatedAt:             order.CreatedAt,
+		UpdatedAt:             order.UpdatedAt,
+	}
+	if order.Discounts != "" {
+		json.Unmarshal([]byte(order.Discounts), &info.Discounts)
+	}
+
+	details, err := p.GetDetails(order.Id)
+	if err != nil {
+		return info, err
+	}
+	for _, detail := range details {
+		info.OrderDetails = append(info.OrderDetails, dto.OrderDetailDTO{
+			Id:          detail.Id,
+			OrderId:     detail.OrderId,
+			ItemId:      detail.ItemId,
+			OrderNo:     detail.OrderNo,
+			Name:        detail.Name,
+			AttrValueId: detail.AttrValueId,
+			Image:       utils.GetImagePath(detail.Image),
+			SKU:         detail.SKU,
+			Price:       detail.Price,
+			PayNum:      detail.PayNum,
+		})
+	}
+
+	return info, nil
+}
+
+// 提交订单，价格由服务端根据商品规格计算，库存在同一事务中扣减
+func (p *OrderService) Submit(uid int, param request.SubmitOrderReq) (order model.Order, err error) {
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		order, err = p.SubmitWithTx(tx, uid, param)
+		return err
+	})
+	return order, err
+}
+
+// 在已有事务中提交订单
+func (p *OrderService) SubmitWithTx(tx *gorm.DB, uid int, param request.SubmitOrderReq) (order model.Order, err error) {
+	if len(param.OrderDetails) == 0 {
+		return order, errors.New("请选择要购买的商品")
+	}
+
+	// 合并相同规格的购买数量
+	payNums := map[int]int{}
+	attrValueIds := []int{}
+	itemIds := map[int]int{}
+	for _, v := range param.OrderDetails {
+		if v.PayNum <= 0 {
+			return order, errors.New("购买数量必须大于0")
+		}
+		if _, ok := payNums[v.AttrValueId]; !ok {
+			attrValueIds = append(attrValueIds, v.AttrValueId)
+		}
+		payNums[v.AttrValueId] += v.PayNum
+		itemIds[v.AttrValueId] = v.ItemId
+	}
+
+	shippingType := model.ShippingType(param.ShippingType)
+	if shippingType == 0 {
+		shippingType = model.ShippingTypeExpress
+	}
+	if shippingType != model.ShippingTypeExpress && shippingType != model.ShippingTypePickup {
+		return order, errors.New("配送方式错误")
+	}
+
+	// 使用地址簿中的收货人信息
+	if param.AddressId > 0 {
+		address, err := NewAddressService().GetUserAddress(uid, param.AddressId)
+		if err != nil {
+			return order, err
+		}
+		param.Realname = address.Realname
+		param.UserPhone = address.Phone
+		param.UserAddress = address.FullAddress()
+	}
+
+	// 门店自提只需要提货人信息，不保存收货地址
+	if shippingType == model.ShippingTypePickup {
+		param.UserAddress = ""
+		if param.Realname == "" || param.UserPhone == "" {
+			return order, errors.New("提货人信息不能为空")
+		}
+	} else if param.Realname == "" || param.UserPhone == "" || param.UserAddress == "" {
+		return order, errors.New("收货人信息不能为空")
+	}
+
+	order = model.Order{
+		Uid:          uid,
+		Realname:     param.Realname,
+		UserPhone:    param.UserPhone,
+		UserAddress:  param.UserAddress,
+		ShippingType: shippingType,
+	}
+
+	details := []model.OrderDetail{}
+	lines := []orderPriceLine{}
+	for _, attrValueId := range attrValueIds {
+		payNum := payNums[attrValueId]
+
+		attrValue := model.ItemAttrValue{}
+		if err := tx.Where("id = ?", attrValueId).First(&attrValue).Error; err != nil {
+			return order, errors.New("商品规格不存在")
+		}
+		if attrValue.ItemId != itemIds[attrValueId] || attrValue.Status != 1 {
+			return order, errors.New("商品规格不存在")
+		}
+
+		item := model.Item{}
+		if err := tx.Where("id = ?", attrValue.ItemId).Where("status = ?", 1).First(&item).Error; err != nil {
+			return order, errors.New("商品不存在或已下架")
+		}
+
+		// 带条件扣减库存，并发下单时只有库存充足的请求能够更新成功
+		result := tx.Model(&model.ItemAttrValue{}).
+			Where("id = ?", attrValue.Id).
+			Where("stock >= ?", payNum).
+			Updates(map[string]interface{}{
+				"stock": gorm.Expr("stock - ?", payNum),
+				"sales": gorm.Expr("sales + ?", payNum),
+			})
+		if result.Error != nil {
+			return order, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return order, errors.New("商品【" + item.Name + "】库存不足")
+		}
+		err = tx.Model(&model.Item{}).
+			Where("id = ?", item.Id).
+			Updates(map[string]interface{}{
+				"stock": gorm.Expr("stock - ?", payNum),
+				"sales": gorm.Expr("sales + ?", payNum),
+			}).Error
+		if err != nil {
+			return order, err
+		}
+
+		// 订单只能包含同一商户的商品
+		if len(details) > 0 && order.MerchantId != item.MerchantId {
+			return order, errors.New("不同店铺的商品请分开下单")
+		}
+
+		order.TotalNum += payNum
+		order.TotalPrice += attrValue.Price * float64(payNum)
+		order.Cost += attrValue.Cost * float64(payNum)
+		order.MerchantId = item.MerchantId
+
+		lines = append(lines, orderPriceLine{
+			ItemId:      item.Id,
+			CategoryIds: parseCategoryIds(item.CategoryIds),
+			Total:       attrValue.Price * float64(payNum),
+		})
+		details = append(details, model.OrderDetail{
+			ItemId:      item.Id,
+			Name:        item.Name,
+			AttrValueId: attrValue.Id,
+			Image:       item.Image,
+			SKU:         attrValue.Suk,
+			Price:       attrValue.Price,
+			Cost:        attrValue.Cost,
+			PayNum:      payNum,
+		})
+	}
+
+	order.TotalPrice = roundPrice(order.TotalPrice)
+	order.Cost = roundPrice(order.Cost)
+
+	// 计算优惠
+	discounts, payPrice, err := p.applyPromotions(tx, uid, param, lines, order.TotalPrice)
+	if err != nil {
+		return order, err
+	}
+	for _, discount := range discounts {
+		if discount.Type == OrderDiscountCoupon {
+			order.CouponId = discount.RefId
+			order.CouponPrice = discount.Amount
+		}
+	}
+	discountsJson, _ := json.Marshal(discounts)
+	order.Discounts = string(discountsJson)
+	order.PayPrice = payPrice
+
+	orderNo, err := p.makeOrderNo(tx)
+	if err != nil {
+		return order, err
+	}
+	order.OrderNo = orderNo
+	if err = tx.Create(&order).Error; err != nil {
+		return order, err
+	}
+
+	for index := range details {
+		details[index].OrderId = order.Id
+		details[index].OrderNo = order.OrderNo
+	}
+	if err = tx.Create(&details).Error; err != nil {
+		return order, err
+	}
+
+	if order.CouponId > 0 {
+		err = NewCouponService().UseWithTx(tx, order.CouponId, order.Id)
+	}
+	return order, err
+}
+
+// 生成唯一订单号
+func (p *OrderService) makeOrderNo(tx *gorm.DB) (string, error) {
+	for i := 0; i < 5; i++ {
+		orderNo := time.Now().Format("20060102150405") + rand.MakeNumeric(8)
+		var count int64
+		if err := tx.Model(&model.Order{}).Unscoped().Where("order_no = ?", orderNo).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return orderNo, nil
+		}
+	}
+	return "", errors.New("生成订单号失败，请重试")
+}
+
+// 金额保留两位小数
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"gorm.io/gorm"
+)
+
+// 订单优惠类型
+const (
+	OrderDiscountCoupon = "coupon" // 优惠券
+)
+
+// 订单商品行，用于计算优惠
+type orderPriceLine struct {
+	ItemId      int
+	CategoryIds []int
+	Total       float64
+}
+
+// 商品是否属于指定分类
+func (p orderPriceLine) inCategories(categoryIds []int) bool {
+	for _, categoryId := range p.CategoryIds {
+		for _, id := range categoryIds {
+			if categoryId == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 订单优惠，price 为前面的优惠执行后的应付金额，不参与时返回 nil
+type orderPromotion func(tx *gorm.DB, uid int, param request.SubmitOrderReq, lines []orderPriceLine, price float64) (*dto.OrderDiscountDTO, error)
+
+// 订单优惠按顺序依次执行，同样的订单和参数总是得到同样的优惠结果
+var orderPromotions = []orderPromotion{
+	couponPromotion,
+}
+
+// 计算订单优惠，返回优惠明细和应付金额，应付金额最低为0.01元
+func (p *OrderService) applyPromotions(tx *gorm.DB, uid int, param request.SubmitOrderReq, lines []orderPriceLine, totalPrice float64) (discounts []dto.OrderDiscountDTO, payPrice float64, err error) {
+	discounts = []dto.OrderDiscountDTO{}
+	payPrice = totalPrice
+	for _, promotion := range orderPromotions {
+		discount, err := promotion(tx, uid, param, lines, payPrice)
+		if err != nil {
+			return discounts, payPrice, err
+		}
+		if discount == nil || discount.Amount <= 0 {
+			continue
+		}
+		if payPrice-discount.Amount < 0.01 {
+			discount.Amount = roundPrice(payPrice - 0.01)
+		}
+		payPrice = roundPrice(payPrice - discount.Amount)
+		discounts = append(discounts, *discount)
+	}
+	return discounts, payPrice, nil
+}
+
+// 优惠券
+func couponPromotion(tx *gorm.DB, uid int, param request.SubmitOrderReq, lines []orderPriceLine, price float64) (*dto.OrderDiscountDTO, error) {
+	if param.CouponId <= 0 {
+		return nil, nil
+	}
+
+	userCoupon, err := NewCouponService().getUsableWithTx(tx, uid, param.CouponId)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := NewCouponService().Calculate(userCoupon, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OrderDiscountDTO{
+		Type:   OrderDiscountCoupon,
+		RefId:  userCoupon.Id,
+		Title:  userCoupon.Title,
+		Amount: amount,
+	}, nil
+}
+
+// 解析商品分类
+func parseCategoryIds(categoryIds string) []int {
+	ids := []int{}
+	if categoryIds != "" {
+		json.Unmarshal([]byte(categoryIds), &ids)
+	}
+	return ids
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/selectfield"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 订单状态流转规则
+type orderTransition struct {
+	Name       string                                         // 事件名称
+	From       []model.OrderStatus                            // 允许流转的订单状态
+	RefundFrom []model.RefundStatus                           // 允许流转的退款状态
+	Guard      func(order model.Order) error                  // 额外校验
+	Apply      func(order model.Order) map[string]interface{} // 流转后需要更新的字段
+	After      func(tx *gorm.DB, order model.Order) error     // 流转后回调，order为流转前的订单
+}
+
+// 未发生退款、退款已被拒绝或仅部分退款
+var refundIdle = []model.RefundStatus{model.RefundStatusNone, model.RefundStatusRejected, model.RefundStatusPartial}
+
+// 可以发起退款的退款状态
+var refundAllowed = []model.RefundStatus{
+	model.RefundStatusNone,
+	model.RefundStatusApplying,
+	model.RefundStatusRejected,
+	model.RefundStatusPartial,
+}
+
+// 可以退款的订单状态
+var refundableStatus = []model.OrderStatus{
+	model.OrderStatusPaid,
+	model.OrderStatusShipped,
+	model.OrderStatusAwaitingPickup,
+	model.OrderStatusCompleted,
+}
+
+// 订单状态机，订单状态只能通过此处定义的事件进行流转
+var orderTransitions = map[model.OrderEvent]orderTransition{
+	model.OrderEventPay: {
+		Name:       "支付",
+		From:       []model.OrderStatus{model.OrderStatusPendingPayment},
+		RefundFrom: []model.RefundStatus{model.RefundStatusNone},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status":   model.OrderStatusPaid,
+				"paid":     1,
+				"pay_time": time.Now(),
+			}
+		},
+		After: func(tx *gorm.DB, order model.Order) error {
+			// 自提订单支付后生成核销码，用户到店出示核销码取货
+			if order.ShippingType != model.ShippingTypePickup || order.VerifyCode != "" {
+				return nil
+			}
+			verifyCode, err := makeVerifyCode(tx)
+			if err != nil {
+				return err
+			}
+			return tx.Model(&model.Order{}).Where("id = ?", order.Id).Update("verify_code", verifyCode).Error
+		},
+	},
+	model.OrderEventCancel: {
+		Name:       "取消",
+		From:       []model.OrderStatus{model.OrderStatusPendingPayment},
+		RefundFrom: []model.RefundStatus{model.RefundStatusNone},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status": model.OrderStatusCancelled,
+			}
+		},
+		After: func(tx *gorm.DB, order model.Order) error {
+			if err := releaseOrderStock(tx, order.Id); err != nil {
+				return err
+			}
+			return NewCouponService().ReleaseWithTx(tx, order.Id)
+		},
+	},
+	model.OrderEventShip: {
+		Name:       "发货",
+		From:       []model.OrderStatus{model.OrderStatusPaid},
+		RefundFrom: refundIdle,
+		Guard: func(order model.Order) error {
+			if order.ShippingType != model.ShippingTypeExpress {
+				return errors.New("自提订单无需发货")
+			}
+			return nil
+		},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status":        model.OrderStatusShipped,
+				"delivery_time": time.Now(),
+			}
+		},
+	},
+	model.OrderEventReadyPickup: {
+		Name:       "备货完成",
+		From:       []model.OrderStatus{model.OrderStatusPaid},
+		RefundFrom: refundIdle,
+		Guard: func(order model.Order) error {
+			if order.ShippingType != model.ShippingTypePickup {
+				return errors.New("快递订单请使用发货操作")
+			}
+			return nil
+		},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status": model.OrderStatusAwaitingPickup,
+			}
+		},
+	},
+	model.OrderEventReceive: {
+		Name:       "确认收货",
+		From:       []model.OrderStatus{model.OrderStatusShipped},
+		RefundFrom: refundIdle,
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status": model.OrderStatusCompleted,
+			}
+		},
+	},
+	// 备货完成是可选步骤，自提订单支付后即可核销
+	model.OrderEventVerify: {
+		Name:       "核销",
+		From:       []model.OrderStatus{model.OrderStatusPaid, model.OrderStatusAwaitingPickup},
+		RefundFrom: refundIdle,
+		Guard: func(order model.Order) error {
+			if order.ShippingType != model.ShippingTypePickup {
+				return errors.New("快递订单无需核销")
+			}
+			return nil
+		},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status":            model.OrderStatusCompleted,
+				"is_merchant_check": 1,
+			}
+		},
+	},
+	model.OrderEventApplyRefund: {
+		Name:       "申请退款",
+		From:       refundableStatus,
+		RefundFrom: refundIdle,
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"refund_status":      model.RefundStatusApplying,
+				"refund_reason_time": time.Now(),
+			}
+		},
+	},
+	model.OrderEventRejectRefund: {
+		Name:       "拒绝退款",
+		From:       refundableStatus,
+		RefundFrom: []model.RefundStatus{model.RefundStatusApplying},
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"refund_status": model.RefundStatusRejected,
+			}
+		},
+	},
+	model.OrderEventPartRefund: {
+		Name:       "部分退款",
+		From:       refundableStatus,
+		RefundFrom: refundAllowed,
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"refund_status": model.RefundStatusPartial,
+			}
+		},
+	},
+	model.OrderEventRefund: {
+		Name:       "退款",
+		From:       refundableStatus,
+		RefundFrom: refundAllowed,
+		Apply: func(order model.Order) map[string]interface{} {
+			return map[string]interface{}{
+				"status":        model.OrderStatusRefunded,
+				"refund_status": model.RefundStatusRefunded,
+			}
+		},
+		After: func(tx *gorm.DB, order model.Order) error {
+			// 全额退款且商品未发出时，库存退回
+			if order.Status == model.OrderStatusPaid || order.Status == model.OrderStatusAwaitingPickup {
+				return releaseOrderStock(tx, order.Id)
+			}
+			return nil
+		},
+	},
+}
+
+// 由状态机维护的字段，不允许通过附加数据修改
+var orderStateColumns = []string{"status", "refund_status", "paid", "is_merchant_check", "verify_code", "refunded_price"}
+
+// 获取订单事件名称
+func (p *OrderService) GetEventName(event model.OrderEvent) string {
+	if transition, ok := orderTransitions[event]; ok {
+		return transition.Name
+	}
+	return string(event)
+}
+
+// 判断订单是否可以执行该事件
+func (p *OrderService) CanTransition(order model.Order, event model.OrderEvent) error {
+	transition, ok := orderTransitions[event]
+	if !ok {
+		return errors.New("未知的订单事件")
+	}
+
+	allowed := false
+	for _, status := range transition.From {
+		if order.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New("订单" + model.OrderStatusNames[order.Status] + "，不能" + transition.Name)
+	}
+
+	allowed = false
+	for _, refundStatus := range transition.RefundFrom {
+		if order.RefundStatus == refundStatus {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New("订单退款" + model.RefundStatusNames[order.RefundStatus] + "，不能" + transition.Name)
+	}
+
+	if transition.Guard != nil {
+		return transition.Guard(order)
+	}
+	return nil
+}
+
+// 订单状态流转
+func (p *OrderService) Transition(param dto.OrderTransitionDTO) (order model.Order, err error) {
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		order, err = p.TransitionWithTx(tx, param)
+		return err
+	})
+	return order, err
+}
+
+// 在已有事务中进行订单状态流转，订单行加锁后校验并记录流转日志
+func (p *OrderService) TransitionWithTx(tx *gorm.DB, param dto.OrderTransitionDTO) (order model.Order, err error) {
+	transition, ok := orderTransitions[param.Event]
+	if !ok {
+		return order, errors.New("未知的订单事件")
+	}
+	if param.ActorType == "" {
+		return order, errors.New("操作人不能为空")
+	}
+
+	before := model.Order{}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", param.OrderId).First(&before).Error
+	if err != nil {
+		return order, errors.New("订单不存在")
+	}
+	if err = p.CanTransition(before, param.Event); err != nil {
+		return order, err
+	}
+
+	// 附加数据中不允许包含状态字段
+	updates := map[string]interface{}{}
+	for key, value := range param.Data {
+		updates[key] = value
+	}
+	for _, column := range orderStateColumns {
+		delete(updates, column)
+	}
+	for key, value := range transition.Apply(before) {
+		updates[key] = value
+	}
+
+	err = tx.Model(&model.Order{}).Where("id = ?", before.Id).Updates(updates).Error
+	if err != nil {
+		return order, err
+	}
+	if err = tx.Where("id = ?", before.Id).First(&order).Error; err != nil {
+		return order, err
+	}
+
+	err = tx.Create(&model.OrderLog{
+		OrderId:          order.Id,
+		Event:            param.Event,
+		FromStatus:       before.Status,
+		ToStatus:         order.Status,
+		FromRefundStatus: before.RefundStatus,
+		ToRefundStatus:   order.RefundStatus,
+		ActorType:        param.ActorType,
+		ActorId:          param.ActorId,
+		Reason:           param.Reason,
+	}).Error
+	if err != nil {
+		return order, err
+	}
+
+	if transition.After != nil {
+		if err = transition.After(tx, before); err != nil {
+			return order, err
+		}
+	}
+	return order, nil
+}
+
+// 订单状态选项
+func (p *OrderService) StatusOptions() (options []selectfield.Option) {
+	for status := model.OrderStatusPendingPayment; status <= model.OrderStatusRefunded; status++ {
+		options = append(options, selectfield.Option{Label: model.OrderStatusNames[status], Value: uint8(status)})
+	}
+	return options
+}
+
+// 退款状态选项
+func (p *OrderService) RefundStatusOptions() (options []selectfield.Option) {
+	for status := model.RefundStatusNone; status <= model.RefundStatusPartial; status++ {
+		options = append(options, selectfield.Option{Label: model.RefundStatusNames[status], Value: uint8(status)})
+	}
+	return options
+}
+
+// 获取订单流转日志
+func (p *OrderService) GetLogs(orderId int) (logs []model.OrderLog, err error) {
+	err = db.Client.Where("order_id = ?", orderId).Order("id asc").Find(&logs).Error
+	return logs, err
+}
+
+// 退回订单占用的库存
+func releaseOrderStock(tx *gorm.DB, orderId int) error {
+	details := []model.OrderDetail{}
+	if err := tx.Where("order_id = ?", orderId).Find(&details).Error; err != nil {
+		return err
+	}
+	for _, detail := range details {
+		updates := map[string]interface{}{
+			"stock": gorm.Expr("stock + ?", detail.PayNum),
+			"sales": gorm.Expr("GREATEST(sales - ?, 0)", detail.PayNum),
+		}
+		err := tx.Model(&model.ItemAttrValue{}).Where("id = ?", detail.AttrValueId).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.Item{}).Where("id = ?", detail.ItemId).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
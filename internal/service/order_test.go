@@ -0,0 +1,154 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+// 创建商品和规格
+func createItem(t *testing.T, stock int, price float64) (model.Item, model.ItemAttrValue) {
+	t.Helper()
+
+	item := model.Item{Name: "测试商品", Price: price, Stock: stock, Status: 1}
+	if err := db.Client.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	attrValue := model.ItemAttrValue{ItemId: item.Id, Suk: "默认", Price: price, Stock: stock, Status: 1}
+	if err := db.Client.Create(&attrValue).Error; err != nil {
+		t.Fatal(err)
+	}
+	return item, attrValue
+}
+
+func submitOrderReq(item model.Item, attrValue model.ItemAttrValue, payNum int) request.SubmitOrderReq {
+	return request.SubmitOrderReq{
+		ShippingType: uint8(model.ShippingTypeExpress),
+		Realname:     "张三",
+		UserPhone:    "13800000000",
+		UserAddress:  "测试地址",
+		OrderDetails: []request.OrderDetail{{ItemId: item.Id, AttrValueId: attrValue.Id, PayNum: payNum}},
+	}
+}
+
+func TestSubmitOrderLastStock(t *testing.T) {
+	setupDB(t, &model.Item{}, &model.ItemAttrValue{}, &model.Order{}, &model.OrderDetail{}, &model.UserCoupon{})
+	item, attrValue := createItem(t, 1, 10)
+
+	// 两个请求同时购买最后一件库存，只有一个能够成功
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = NewOrderService().Submit(i+1, submitOrderReq(item, attrValue, 1))
+		}(i)
+	}
+	wg.Wait()
+
+	if (errs[0] == nil) == (errs[1] == nil) {
+		t.Fatalf("expected exactly one submit to succeed: %v", errs)
+	}
+	db.Client.First(&attrValue, attrValue.Id)
+	db.Client.First(&item, item.Id)
+	if attrValue.Stock != 0 || attrValue.Sales != 1 || item.Stock != 0 || item.Sales != 1 {
+		t.Fatalf("unexpected stock: %+v %+v", attrValue, item)
+	}
+	var count int64
+	db.Client.Model(&model.Order{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 order, got %d", count)
+	}
+}
+
+func TestOrderTransitionRejectsIllegal(t *testing.T) {
+	setupDB(t, &model.Order{}, &model.OrderLog{})
+	pending := model.Order{OrderNo: "T001", ShippingType: model.ShippingTypeExpress, Status: model.OrderStatusPendingPayment}
+	pickup := model.Order{OrderNo: "T002", Paid: 1, ShippingType: model.ShippingTypePickup, Status: model.OrderStatusPaid}
+	completed := model.Order{OrderNo: "T003", Paid: 1, ShippingType: model.ShippingTypeExpress, Status: model.OrderStatusCompleted}
+	if err := db.Client.Create([]*model.Order{&pending, &pickup, &completed}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		order model.Order
+		event model.OrderEvent
+	}{
+		{pending, model.OrderEventShip},
+		{pending, model.OrderEventReceive},
+		{pending, model.OrderEventVerify},
+		{pickup, model.OrderEventShip},
+		{pickup, model.OrderEventPay},
+		{completed, model.OrderEventCancel},
+		{completed, model.OrderEventReceive},
+	}
+	for _, c := range cases {
+		_, err := NewOrderService().Transition(dto.OrderTransitionDTO{OrderId: c.order.Id, Event: c.event, ActorType: model.OrderActorAdmin})
+		if err == nil {
+			t.Fatalf("expected %s on order %s to fail", c.event, c.order.OrderNo)
+		}
+		order := model.Order{}
+		db.Client.First(&order, c.order.Id)
+		if order.Status != c.order.Status {
+			t.Fatalf("order %s status changed to %d", order.OrderNo, order.Status)
+		}
+	}
+
+	var count int64
+	db.Client.Model(&model.OrderLog{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no order logs, got %d", count)
+	}
+}
+
+func TestCancelOrderReleasesCoupon(t *testing.T) {
+	setupDB(t, &model.Item{}, &model.ItemAttrValue{}, &model.Order{}, &model.OrderDetail{}, &model.OrderLog{}, &model.UserCoupon{})
+	item, attrValue := createItem(t, 5, 10)
+	userCoupon := model.UserCoupon{
+		Uid:       1,
+		Title:     "满10减3",
+		Type:      model.CouponTypeFixed,
+		Amount:    3,
+		MinPrice:  10,
+		StartTime: datetime.Datetime{Time: time.Now().Add(-time.Hour)},
+		EndTime:   datetime.Datetime{Time: time.Now().Add(time.Hour)},
+	}
+	if err := db.Client.Create(&userCoupon).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	param := submitOrderReq(item, attrValue, 2)
+	param.CouponId = userCoupon.Id
+	order, err := NewOrderService().Submit(1, param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.CouponId != userCoupon.Id || order.PayPrice != 17 {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+	db.Client.First(&userCoupon, userCoupon.Id)
+	if userCoupon.Status != model.UserCouponStatusUsed || userCoupon.OrderId != order.Id {
+		t.Fatalf("coupon not used: %+v", userCoupon)
+	}
+
+	// 取消订单退回优惠券和库存
+	_, err = NewOrderService().Transition(dto.OrderTransitionDTO{OrderId: order.Id, Event: model.OrderEventCancel, ActorType: model.OrderActorUser, ActorId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Client.First(&userCoupon, userCoupon.Id)
+	if userCoupon.Status != model.UserCouponStatusUnused || userCoupon.OrderId != 0 {
+		t.Fatalf("coupon not released: %+v", userCoupon)
+	}
+	db.Client.First(&attrValue, attrValue.Id)
+	if attrValue.Stock != 5 || attrValue.Sales != 0 {
+		t.Fatalf("stock not released: %+v", attrValue)
+	}
+}
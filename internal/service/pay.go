@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PayService struct{}
+
+func NewPayService() *PayService {
+	return &PayService{}
+}
+
+// 获取当前配置的支付渠道，默认为微信支付
+func (p *PayService) GetChannel() string {
+	channel := utils.GetConfig("PAY_CHANNEL")
+	if channel == "" {
+		channel = pay.ChannelWechat
+	}
+	return channel
+}
+
+// 获取支付网关，渠道为空时使用当前配置的支付渠道
+func (p *PayService) GetGateway(channel string) (pay.Gateway, error) {
+	if channel == "" {
+		channel = p.GetChannel()
+	}
+	return pay.GetGateway(channel)
+}
+
+// 发起订单支付
+func (p *PayService) CreatePayment(order model.Order, scene string, openId string, clientIp string) (*pay.PaymentResult, error) {
+	if err := NewOrderService().CanTransition(order, model.OrderEventPay); err != nil {
+		return nil, err
+	}
+	if NewOrderService().IsPayExpired(order) {
+		return nil, errors.New("订单已超时，请重新下单")
+	}
+
+	gateway, err := p.GetGateway("")
+	if err != nil {
+		return nil, err
+	}
+
+	// 订单标题使用第一个商品的名称
+	subject := order.OrderNo
+	details, _ := NewOrderService().GetDetails(order.Id)
+	if len(details) > 0 {
+		subject = details[0].Name
+		if len(details) > 1 {
+			subject = subject + "等" + strconv.Itoa(len(details)) + "件商品"
+		}
+	}
+
+	result, err := gateway.CreatePayment(context.Background(), pay.PaymentRequest{
+		OrderNo:   order.OrderNo,
+		Amount:    order.PayPrice,
+		Subject:   subject,
+		Scene:     scene,
+		OpenId:    openId,
+		ClientIp:  clientIp,
+		NotifyUrl: utils.GetDomain() + "/api/pay/" + gateway.Channel() + "/notify",
+		ExpireAt:  order.CreatedAt.Time.Add(NewOrderService().GetPayTimeout()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录发起支付的渠道，用于查单、关单和对账
+	err = db.Client.Model(&model.Order{}).Where("id = ?", order.Id).Update("pay_type", gateway.Channel()).Error
+	return result, err
+}
+
+// 处理支付平台异步通知，重复通知直接返回成功
+func (p *PayService) HandleNotification(ctx context.Context, notification *pay.Notification) error {
+	if notification == nil || notification.OrderNo == "" {
+		return errors.New("通知参数错误")
+	}
+
+	// 未成功的支付通知无需处理，退款失败时标记退款单失败
+	if !notification.Success {
+		if notification.Type == pay.NotifyTypeRefund && notification.RefundNo != "" {
+			return NewRefundService().Fail(notification.RefundNo, notification.Channel+"退款失败通知")
+		}
+		return nil
+	}
+
+	var refund model.OrderRefund
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		order := model.Order{}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_no = ?", notification.OrderNo).First(&order).Error
+		if err != nil {
+			return errors.New("订单不存在")
+		}
+
+		switch notification.Type {
+		case pay.NotifyTypePayment:
+			refund, err = p.paid(tx, order, notification)
+			return err
+		case pay.NotifyTypeRefund:
+			return p.refunded(tx, order, notification)
+		}
+		return errors.New("未知的通知类型")
+	})
+	if err != nil || refund.Id == 0 {
+		return err
+	}
+
+	// 通知已处理，自动退款失败时退款单保持退款中，由同步退款单的任务重新提交
+	if _, err = NewRefundService().Submit(ctx, refund); err != nil {
+		log.Println("订单" + notification.OrderNo + "自动退款错误：" + err.Error())
+	}
+	return nil
+}
+
+// 支付成功，订单已取消时返回需要自动退款的退款单
+func (p *PayService) paid(tx *gorm.DB, order model.Order, notification *pay.Notification) (model.OrderRefund, error) {
+	if order.Paid == 1 {
+		return model.OrderRefund{}, nil
+	}
+	if math.Abs(order.PayPrice-notification.Amount) >= 0.01 {
+		return model.OrderRefund{}, errors.New("支付金额与订单金额不一致")
+	}
+	if order.Status == model.OrderStatusCancelled {
+		return p.paidCancelled(tx, order, notification)
+	}
+
+	_, err := NewOrderService().TransitionWithTx(tx, dto.OrderTransitionDTO{
+		OrderId:   order.Id,
+		Event:     model.OrderEventPay,
+		ActorType: model.OrderActorSystem,
+		Reason:    notification.Channel + "支付通知",
+		Data: map[string]interface{}{
+			"pay_type":       notification.Channel,
+			"transaction_id": notification.TransactionId,
+		},
+	})
+	if err != nil {
+		return model.OrderRefund{}, err
+	}
+
+	return model.OrderRefund{}, p.postPaid(tx, order, notification)
+}
+
+// 记录订单支付账单，在线支付只记录流水不扣减余额
+func (p *PayService) postPaid(tx *gorm.DB, order model.Order, notification *pay.Notification) error {
+	_, err := NewBillService().PostWithTx(tx, dto.BillPostDTO{
+		Uid:      order.Uid,
+		PM:       model.BillPMExpense,
+		Title:    "购买商品",
+		Category: model.BillCategoryPay,
+		Type:     model.BillTypePayOrder,
+		Number:   notification.Amount,
+		LinkId:   order.OrderNo,
+		Mark:     notification.Channel + "支付订单" + order.OrderNo,
+	})
+	return err
+}
+
+// 订单取消后才收到支付，库存和优惠券已释放，记录支付信息后创建退款单原路退回
+func (p *PayService) paidCancelled(tx *gorm.DB, order model.Order, notification *pay.Notification) (model.OrderRefund, error) {
+	err := tx.Model(&model.Order{}).Where("id = ?", order.Id).Updates(map[string]interface{}{
+		"paid":           1,
+		"pay_time":       time.Now(),
+		"pay_type":       notification.Channel,
+		"transaction_id": notification.TransactionId,
+	}).Error
+	if err != nil {
+		return model.OrderRefund{}, err
+	}
+	if err = p.postPaid(tx, order, notification); err != nil {
+		return model.OrderRefund{}, err
+	}
+
+	return NewRefundService().CreateWithTx(tx, order.Id, notification.Amount, "订单已取消，自动退款", model.OrderActorSystem, 0)
+}
+
+// 退款成功，按退款单完成退款
+func (p *PayService) refunded(tx *gorm.DB, order model.Order, notification *pay.Notification) error {
+	refund := model.OrderRefund{}
+	if notification.RefundNo != "" {
+		tx.Where("refund_no = ?", notification.RefundNo).Limit(1).Find(&refund)
+	}
+	if refund.Id == 0 {
+		var err error
+		if refund, err = p.backfillRefund(tx, order, notification); err != nil || refund.Id == 0 {
+			return err
+		}
+	}
+
+	return NewRefundService().CompleteWithTx(tx, refund.RefundNo, "")
+}
+
+// 支付平台后台发起的退款没有本地退款单，按通知补录
+//
+// 通知只有累计退款金额时，本次退款金额为累计退款金额减去已退款金额，累计金额已全部记录的重复通知直接忽略；
+// 通知没有退款单号时按订单生成退款单号
+func (p *PayService) backfillRefund(tx *gorm.DB, order model.Order, notification *pay.Notification) (model.OrderRefund, error) {
+	amount := roundPrice(notification.Amount)
+	if amount <= 0 && notification.RefundTotal > 0 {
+		amount = roundPrice(notification.RefundTotal - order.RefundedPrice)
+	}
+	if amount <= 0 {
+		return model.OrderRefund{}, nil
+	}
+
+	refundNo := notification.RefundNo
+	if refundNo == "" {
+		var err error
+		if refundNo, err = NewRefundService().makeRefundNo(tx, order); err != nil {
+			return model.OrderRefund{}, err
+		}
+	}
+
+	refund := model.OrderRefund{
+		OrderId:   order.Id,
+		RefundNo:  refundNo,
+		Amount:    amount,
+		Reason:    notification.Channel + "退款通知",
+		ActorType: model.OrderActorSystem,
+		Status:    model.OrderRefundStatusProcessing,
+	}
+	err := tx.Create(&refund).Error
+	return refund, err
+}
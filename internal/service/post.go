@@ -1,9 +1,24 @@
 package service
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"strconv"
+
 	"github.com/quarkcloudio/quark-go/v3/dal/db"
 	"github.com/quarkcloudio/quark-go/v3/template/admin/component/form/fields/treeselect"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/response"
 	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// 访问密码错误
+var (
+	ErrPostPasswordRequired = errors.New("请输入访问密码")
+	ErrPostPasswordWrong    = errors.New("访问密码错误")
 )
 
 type PostService struct{}
@@ -49,3 +64,170 @@ func (p *PostService) FindTreeSelectNode(pid int) (list []treeselect.TreeData) {
 	}
 	return list
 }
+
+// 获取文章分页列表
+func (p *PostService) GetArticlePage(param request.ArticleIndexQueryReq) (list []response.ArticleIndexResp, total int64, err error) {
+	list = make([]response.ArticleIndexResp, 0)
+	query := db.Client.Model(model.Post{}).Where("type = ?", "ARTICLE").Where("status = ?", 1)
+
+	// 分类筛选，包含子分类
+	if param.CategoryId > 0 {
+		query = query.Where("category_id IN ?", NewCategoryService().GetDescendantIds(param.CategoryId, "ARTICLE"))
+	}
+
+	// 推荐位筛选
+	if param.Position > 0 {
+		query = query.Where("JSON_CONTAINS(position, ?)", strconv.Itoa(param.Position))
+	}
+
+	// 标题、标签模糊搜索
+	if param.Keyword != "" {
+		keyword := "%" + param.Keyword + "%"
+		query = query.Where("title LIKE ? OR tags LIKE ?", keyword, keyword)
+	}
+
+	if err = query.Count(&total).Error; err != nil {
+		return list, total, err
+	}
+
+	page, pageSize := param.Page, param.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	posts := []model.Post{}
+	err = query.
+		Omit("content").
+		Order("level desc, id desc").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+	if err != nil {
+		return list, total, err
+	}
+
+	for _, post := range posts {
+		list = append(list, response.ArticleIndexResp{
+			Id:          post.Id,
+			CategoryId:  post.CategoryId,
+			Title:       post.Title,
+			Author:      post.Author,
+			Source:      post.Source,
+			Description: post.Description,
+			Tags:        post.Tags,
+			ShowType:    post.ShowType,
+			Covers:      p.getCovers(post.CoverIds),
+			Link:        post.Link,
+			View:        post.View,
+			Comment:     post.Comment,
+			Locked:      post.Password != "",
+			CreatedAt:   post.CreatedAt,
+		})
+	}
+
+	return list, total, nil
+}
+
+// 获取文章详情，设置了访问密码的文章需校验密码，获取成功后浏览量加一
+func (p *PostService) GetArticleDetail(id int, password string) (detail response.PostDetailResp, err error) {
+	post := model.Post{}
+	err = db.Client.
+		Where("id = ?", id).
+		Where("type = ?", "ARTICLE").
+		Where("status = ?", 1).
+		First(&post).Error
+	if err != nil {
+		return detail, errors.New("文章不存在")
+	}
+	return p.getDetail(post, password)
+}
+
+// 通过缩略名获取单页详情
+func (p *PostService) GetPageDetail(name string, password string) (detail response.PostDetailResp, err error) {
+	post := model.Post{}
+	err = db.Client.
+		Where("name = ?", name).
+		Where("type = ?", "PAGE").
+		Where("status = ?", 1).
+		First(&post).Error
+	if err != nil {
+		return detail, errors.New("页面不存在")
+	}
+	return p.getDetail(post, password)
+}
+
+// 校验访问密码并组装详情，密码校验未通过时只返回标题等基本信息
+func (p *PostService) getDetail(post model.Post, password string) (detail response.PostDetailResp, err error) {
+	if post.Password != "" {
+		basic := response.PostDetailResp{
+			Id:         post.Id,
+			CategoryId: post.CategoryId,
+			Title:      post.Title,
+			Name:       post.Name,
+			PageTpl:    post.PageTpl,
+		}
+		if password == "" {
+			return basic, ErrPostPasswordRequired
+		}
+		if subtle.ConstantTimeCompare([]byte(post.Password), []byte(password)) != 1 {
+			return basic, ErrPostPasswordWrong
+		}
+	}
+
+	// 浏览量加一，不更新修改时间
+	db.Client.Model(model.Post{}).Where("id = ?", post.Id).UpdateColumn("view", gorm.Expr("view + 1"))
+
+	return response.PostDetailResp{
+		Id:          post.Id,
+		CategoryId:  post.CategoryId,
+		Title:       post.Title,
+		Name:        post.Name,
+		Author:      post.Author,
+		Source:      post.Source,
+		Description: post.Description,
+		Tags:        post.Tags,
+		Covers:      p.getCovers(post.CoverIds),
+		Link:        post.Link,
+		Content:     utils.ReplaceContentSrc(post.Content),
+		Files:       p.getFiles(post.FileIds),
+		View:        post.View + 1,
+		Comment:     post.Comment,
+		PageTpl:     post.PageTpl,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+	}, nil
+}
+
+// 获取封面图地址
+func (p *PostService) getCovers(coverIds string) []string {
+	covers := []string{}
+	if coverIds == "" {
+		return covers
+	}
+	return append(covers, utils.GetImagePaths(coverIds)...)
+}
+
+// 获取附件名称和地址
+func (p *PostService) getFiles(fileIds string) []response.PostFileResp {
+	files := []response.PostFileResp{}
+	if fileIds == "" {
+		return files
+	}
+
+	// 附件字段保存的是包含名称和地址的 Json 数组
+	items := []struct {
+		Name string `json:"name"`
+	}{}
+	json.Unmarshal([]byte(fileIds), &items)
+	for index, url := range utils.GetFilePaths(fileIds) {
+		file := response.PostFileResp{Url: url}
+		if index < len(items) {
+			file.Name = items[index].Name
+		}
+		files = append(files, file)
+	}
+	return files
+}
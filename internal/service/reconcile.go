@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReconcileService struct{}
+
+func NewReconcileService() *ReconcileService {
+	return &ReconcileService{}
+}
+
+// 同步未超时的待支付订单的支付状态，已支付的补单
+//
+// 按id分批处理，单个订单出错时继续处理后续订单，错误汇总后返回
+func (p *ReconcileService) SyncPendingOrders(ctx context.Context) error {
+	errs := []error{}
+	lastId := 0
+	for {
+		orders := []model.Order{}
+		err := db.Client.WithContext(ctx).
+			Where("id > ?", lastId).
+			Where("status = ?", model.OrderStatusPendingPayment).
+			Where("pay_type <> ?", "").
+			Where("created_at < ?", time.Now().Add(-time.Minute)).
+			Where("created_at >= ?", time.Now().Add(-NewOrderService().GetPayTimeout())).
+			Order("id asc").
+			Limit(100).
+			Find(&orders).Error
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+			lastId = order.Id
+			if _, err := p.syncPendingOrder(ctx, order); err != nil {
+				errs = append(errs, errors.New("订单"+order.OrderNo+"："+err.Error()))
+			}
+		}
+		if len(orders) < 100 {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// 取消超时未支付的订单，取消时释放库存和优惠券，并关闭支付平台的交易
+//
+// 按id分批处理，查单失败的订单保持待支付，下次执行时重试，不阻塞后续订单
+func (p *ReconcileService) CancelExpiredOrders(ctx context.Context) error {
+	errs := []error{}
+	lastId := 0
+	for {
+		orders := []model.Order{}
+		err := db.Client.WithContext(ctx).
+			Where("id > ?", lastId).
+			Where("status = ?", model.OrderStatusPendingPayment).
+			Where("created_at < ?", time.Now().Add(-NewOrderService().GetPayTimeout())).
+			Order("id asc").
+			Limit(100).
+			Find(&orders).Error
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+			lastId = order.Id
+			if err := p.cancelExpiredOrder(ctx, order); err != nil {
+				errs = append(errs, errors.New("订单"+order.OrderNo+"："+err.Error()))
+			}
+		}
+		if len(orders) < 100 {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// 向支付平台查单，已支付的按支付通知处理
+func (p *ReconcileService) syncPendingOrder(ctx context.Context, order model.Order) (paid bool, err error) {
+	gateway, err := NewPayService().GetGateway(order.PayType)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := gateway.QueryPayment(ctx, order.OrderNo)
+	if err != nil || result.Status != pay.PaymentStatusPaid {
+		return false, err
+	}
+
+	return true, NewPayService().HandleNotification(ctx, &pay.Notification{
+		Channel:       gateway.Channel(),
+		Type:          pay.NotifyTypePayment,
+		OrderNo:       order.OrderNo,
+		TransactionId: result.TransactionId,
+		Amount:        result.Amount,
+		Success:       true,
+	})
+}
+
+// 取消单个超时订单
+func (p *ReconcileService) cancelExpiredOrder(ctx context.Context, order model.Order) error {
+
+	// 已发起过支付，先查单避免取消已付款的订单，查单失败时不取消，再关闭支付平台的交易
+	if order.PayType != "" {
+		paid, err := p.syncPendingOrder(ctx, order)
+		if err != nil || paid {
+			return err
+		}
+
+		gateway, err := NewPayService().GetGateway(order.PayType)
+		if err != nil {
+			return err
+		}
+
+		// 关单失败不影响取消订单，用户未付款的订单在支付平台也会自动过期
+		gateway.Close(ctx, order.OrderNo)
+	}
+
+	return db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := NewOrderService().TransitionWithTx(tx, dto.OrderTransitionDTO{
+			OrderId:   order.Id,
+			Event:     model.OrderEventCancel,
+			ActorType: model.OrderActorSystem,
+			Reason:    "支付超时",
+		})
+		return err
+	})
+}
+
+// 对账，比较本地订单与支付平台指定日期的交易账单，并保存对账报告
+func (p *ReconcileService) Reconcile(ctx context.Context, channel string, date time.Time) (model.PayReconciliation, error) {
+	report := model.PayReconciliation{
+		Channel:  channel,
+		BillDate: date.Format(time.DateOnly),
+	}
+
+	gateway, err := NewPayService().GetGateway(channel)
+	if err != nil {
+		return report, err
+	}
+	downloader, ok := gateway.(pay.BillDownloader)
+	if !ok {
+		return report, errors.New("该支付渠道不支持下载对账单")
+	}
+	records, err := downloader.DownloadBill(ctx, date)
+	if err != nil {
+		return report, err
+	}
+
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+	locals, err := p.getLocalRecords(ctx, channel, start, end)
+	if err != nil {
+		return report, err
+	}
+
+	remotes := map[string]pay.BillRecord{}
+	for _, record := range records {
+		report.RemoteCount++
+		report.RemoteAmount += record.Amount
+
+		// 同一订单的多笔部分退款合并比较
+		key := record.Type + ":" + record.OrderNo
+		if remote, ok := remotes[key]; ok {
+			record.Amount = roundPrice(remote.Amount + record.Amount)
+		}
+		remotes[key] = record
+	}
+	for _, record := range locals {
+		report.LocalCount++
+		report.LocalAmount += record.Amount
+	}
+
+	diffs := []dto.ReconcileDiffDTO{}
+	for key, local := range locals {
+		remote, ok := remotes[key]
+		if !ok {
+			diffs = append(diffs, dto.ReconcileDiffDTO{
+				Type:        local.Type,
+				OrderNo:     local.OrderNo,
+				Reason:      "支付平台无此记录",
+				LocalAmount: local.Amount,
+			})
+			continue
+		}
+		if math.Abs(local.Amount-remote.Amount) >= 0.01 {
+			diffs = append(diffs, dto.ReconcileDiffDTO{
+				Type:         local.Type,
+				OrderNo:      local.OrderNo,
+				Reason:       "金额不一致",
+				LocalAmount:  local.Amount,
+				RemoteAmount: remote.Amount,
+			})
+		}
+	}
+	for key, remote := range remotes {
+		if _, ok := locals[key]; !ok {
+			diffs = append(diffs, dto.ReconcileDiffDTO{
+				Type:         remote.Type,
+				OrderNo:      remote.OrderNo,
+				Reason:       "本地无此记录",
+				RemoteAmount: remote.Amount,
+			})
+		}
+	}
+
+	diffsJson, _ := json.Marshal(diffs)
+	report.LocalAmount = roundPrice(report.LocalAmount)
+	report.RemoteAmount = roundPrice(report.RemoteAmount)
+	report.DiffCount = len(diffs)
+	report.Diffs = string(diffsJson)
+	report.Status = 1
+	if len(diffs) > 0 {
+		report.Status = 0
+	}
+
+	// 同一渠道同一日期重复对账时覆盖之前的报告
+	err = db.Client.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "channel"}, {Name: "bill_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"local_count", "local_amount", "remote_count", "remote_amount", "diff_count", "diffs", "status", "updated_at"}),
+	}).Create(&report).Error
+
+	return report, err
+}
+
+// 获取本地指定时间段内的支付、退款记录
+func (p *ReconcileService) getLocalRecords(ctx context.Context, channel string, start time.Time, end time.Time) (map[string]pay.BillRecord, error) {
+	records := map[string]pay.BillRecord{}
+
+	orders := []model.Order{}
+	err := db.Client.WithContext(ctx).
+		Where("paid = ?", 1).
+		Where("pay_type = ?", channel).
+		Where("pay_time >= ? AND pay_time < ?", start, end).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		records[pay.NotifyTypePayment+":"+order.OrderNo] = pay.BillRecord{
+			Type:          pay.NotifyTypePayment,
+			OrderNo:       order.OrderNo,
+			TransactionId: order.TransactionId,
+			Amount:        order.PayPrice,
+		}
+	}
+
+	// 一个订单可能多次部分退款，按订单合计退款金额
+	refunds := []struct {
+		OrderNo       string
+		TransactionId string
+		Amount        float64
+	}{}
+	err = db.Client.WithContext(ctx).
+		Model(&model.OrderRefund{}).
+		Joins("JOIN orders ON orders.id = order_refunds.order_id").
+		Where("orders.pay_type = ?", channel).
+		Where("order_refunds.status = ?", model.OrderRefundStatusSuccess).
+		Where("order_refunds.refunded_at >= ? AND order_refunds.refunded_at < ?", start, end).
+		Group("orders.order_no, orders.transaction_id").
+		Select("orders.order_no, orders.transaction_id, SUM(order_refunds.amount) AS amount").
+		Scan(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, refund := range refunds {
+		records[pay.NotifyTypeRefund+":"+refund.OrderNo] = pay.BillRecord{
+			Type:          pay.NotifyTypeRefund,
+			OrderNo:       refund.OrderNo,
+			TransactionId: refund.TransactionId,
+			Amount:        roundPrice(refund.Amount),
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto/request"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 退款凭证图片最大数量
+const RefundImageLimit = 9
+
+type RefundService struct{}
+
+func NewRefundService() *RefundService {
+	return &RefundService{}
+}
+
+// 用户申请退款，支持部分退款
+func (p *RefundService) Apply(uid int, param request.OrderRefundReq) (model.Order, error) {
+	order, err := NewOrderService().GetUserOrderByOrderNo(uid, param.OrderNo)
+	if err != nil {
+		return order, err
+	}
+	if param.RefundReason == "" {
+		return order, errors.New("请选择退款原因")
+	}
+	if len(param.RefundReasonImg) > RefundImageLimit {
+		return order, errors.New("退款凭证最多上传9张图片")
+	}
+
+	// 可退金额为实付金额减去已退款金额
+	refundable := roundPrice(order.PayPrice - order.RefundedPrice)
+	refundPrice := roundPrice(param.RefundPrice)
+	if refundPrice == 0 {
+		refundPrice = refundable
+	}
+	if refundPrice < 0 || refundPrice > refundable {
+		return order, errors.New("退款金额不能超过可退金额")
+	}
+
+	images := ""
+	if len(param.RefundReasonImg) > 0 {
+		data, _ := json.Marshal(param.RefundReasonImg)
+		images = string(data)
+	}
+
+	return NewOrderService().Transition(dto.OrderTransitionDTO{
+		OrderId:   order.Id,
+		Event:     model.OrderEventApplyRefund,
+		ActorType: model.OrderActorUser,
+		ActorId:   uid,
+		Reason:    param.RefundReason,
+		Data: map[string]interface{}{
+			"refund_price":            refundPrice,
+			"refund_reason":           param.RefundReason,
+			"refund_reason_explain":   param.RefundReasonExplain,
+			"refund_reason_img":       images,
+			"refund_rejection_reason": "",
+		},
+	})
+}
+
+// 同意退款，原路退回退款金额，每次退款生成新的退款单，一个订单可以多次部分退款
+//
+// 支付平台同步返回退款成功时直接完成退款，否则等待退款异步通知
+func (p *RefundService) Approve(order model.Order, adminId int, refundPrice float64) (model.OrderRefund, error) {
+	if order.RefundStatus != model.RefundStatusApplying {
+		return model.OrderRefund{}, errors.New("订单未申请退款")
+	}
+	if err := NewOrderService().CanTransition(order, model.OrderEventRefund); err != nil {
+		return model.OrderRefund{}, err
+	}
+	if order.Paid != 1 || order.PayType == "" {
+		return model.OrderRefund{}, errors.New("订单未在线支付，不能原路退款")
+	}
+
+	refundPrice = roundPrice(refundPrice)
+	if refundPrice <= 0 {
+		refundPrice = order.RefundPrice
+	}
+
+	var refund model.OrderRefund
+	err := db.Client.Transaction(func(tx *gorm.DB) (err error) {
+		refund, err = p.CreateWithTx(tx, order.Id, refundPrice, order.RefundReason, model.OrderActorAdmin, adminId)
+		return err
+	})
+	if err != nil {
+		return refund, err
+	}
+
+	return p.Submit(context.Background(), refund)
+}
+
+// 在已有事务中创建退款单，订单行加锁后校验可退金额
+//
+// 订单存在金额相同的退款中的退款单时直接返回该退款单，重新提交时使用原退款单号，支付平台不会重复退款；
+// 金额不同时返回错误，需等待该退款单完成后再发起
+func (p *RefundService) CreateWithTx(tx *gorm.DB, orderId int, amount float64, reason string, actorType string, actorId int) (model.OrderRefund, error) {
+	refund := model.OrderRefund{}
+	order := model.Order{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderId).First(&order).Error
+	if err != nil {
+		return refund, errors.New("订单不存在")
+	}
+
+	amount = roundPrice(amount)
+	tx.Where("order_id = ? AND status = ?", order.Id, model.OrderRefundStatusProcessing).Limit(1).Find(&refund)
+	if refund.Id > 0 {
+		if refund.Amount != amount {
+			return model.OrderRefund{}, errors.New("订单有退款中的退款单，金额为" + strconv.FormatFloat(refund.Amount, 'f', 2, 64) + "元，请等待退款完成后再操作")
+		}
+		return refund, nil
+	}
+
+	if amount <= 0 || amount > roundPrice(order.PayPrice-order.RefundedPrice) {
+		return refund, errors.New("退款金额不能超过可退金额")
+	}
+
+	refundNo, err := p.makeRefundNo(tx, order)
+	if err != nil {
+		return refund, err
+	}
+	refund = model.OrderRefund{
+		OrderId:   order.Id,
+		RefundNo:  refundNo,
+		Amount:    amount,
+		Reason:    reason,
+		ActorType: actorType,
+		ActorId:   actorId,
+		Status:    model.OrderRefundStatusProcessing,
+	}
+	err = tx.Create(&refund).Error
+	return refund, err
+}
+
+// 生成退款单号，订单号加退款序号，需在订单行加锁后调用
+func (p *RefundService) makeRefundNo(tx *gorm.DB, order model.Order) (string, error) {
+	var count int64
+	if err := tx.Model(&model.OrderRefund{}).Where("order_id = ?", order.Id).Count(&count).Error; err != nil {
+		return "", err
+	}
+	return order.OrderNo + "R" + strconv.FormatInt(count+1, 10), nil
+}
+
+// 向支付平台提交退款单
+//
+// 请求支付平台出错时退款单保持退款中，可能已经退款成功，需使用同一退款单号重新提交
+func (p *RefundService) Submit(ctx context.Context, refund model.OrderRefund) (model.OrderRefund, error) {
+	order := model.Order{}
+	if err := db.Client.WithContext(ctx).Where("id = ?", refund.OrderId).First(&order).Error; err != nil {
+		return refund, errors.New("订单不存在")
+	}
+
+	gateway, err := NewPayService().GetGateway(order.PayType)
+	if err != nil {
+		return refund, err
+	}
+
+	result, err := gateway.Refund(ctx, pay.RefundRequest{
+		OrderNo:     order.OrderNo,
+		RefundNo:    refund.RefundNo,
+		Amount:      refund.Amount,
+		TotalAmount: order.PayPrice,
+		Reason:      refund.Reason,
+		NotifyUrl:   utils.GetDomain() + "/api/pay/" + gateway.Channel() + "/notify",
+	})
+	if err != nil {
+		db.Client.Model(&model.OrderRefund{}).Where("id = ?", refund.Id).Update("error", err.Error())
+		return refund, errors.New("退款失败：" + err.Error())
+	}
+	if result.Status == pay.RefundStatusFailed {
+		return refund, p.Fail(refund.RefundNo, "支付平台退款失败")
+	}
+	if result.Status != pay.RefundStatusSuccess {
+		err = db.Client.WithContext(ctx).Model(&model.OrderRefund{}).Where("id = ?", refund.Id).Update("refund_id", result.RefundId).Error
+		return refund, err
+	}
+
+	err = db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return p.CompleteWithTx(tx, refund.RefundNo, result.RefundId)
+	})
+	if err != nil {
+		return refund, err
+	}
+	err = db.Client.WithContext(ctx).Where("id = ?", refund.Id).First(&refund).Error
+	return refund, err
+}
+
+// 重新提交长时间未完成的退款单，支付平台按退款单号去重，已退款的直接返回退款结果
+func (p *RefundService) SubmitProcessing(ctx context.Context) error {
+	refunds := []model.OrderRefund{}
+	err := db.Client.WithContext(ctx).
+		Where("status = ?", model.OrderRefundStatusProcessing).
+		Where("updated_at < ?", time.Now().Add(-5*time.Minute)).
+		Order("updated_at asc").
+		Limit(100).
+		Find(&refunds).Error
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, refund := range refunds {
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		// 更新时间后移，提交失败的退款单排到下一批的末尾，不阻塞其他退款单
+		db.Client.WithContext(ctx).Model(&model.OrderRefund{}).Where("id = ?", refund.Id).Update("updated_at", time.Now())
+		if _, err := p.Submit(ctx, refund); err != nil {
+			errs = append(errs, errors.New("退款单"+refund.RefundNo+"："+err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 在已有事务中完成退款，重复完成直接返回
+//
+// 累计退款金额达到实付金额时订单流转为已退款并退回库存，否则为部分退款；已取消订单的退款不变更订单状态
+func (p *RefundService) CompleteWithTx(tx *gorm.DB, refundNo string, refundId string) error {
+	refund := model.OrderRefund{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("refund_no = ?", refundNo).First(&refund).Error
+	if err != nil {
+		return errors.New("退款单不存在")
+	}
+	if refund.Status == model.OrderRefundStatusSuccess {
+		return nil
+	}
+
+	order := model.Order{}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refund.OrderId).First(&order).Error
+	if err != nil {
+		return errors.New("订单不存在")
+	}
+
+	updates := map[string]interface{}{
+		"status":      model.OrderRefundStatusSuccess,
+		"error":       "",
+		"refunded_at": time.Now(),
+	}
+	if refundId != "" {
+		updates["refund_id"] = refundId
+	}
+	if err = tx.Model(&model.OrderRefund{}).Where("id = ?", refund.Id).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	refundedPrice := roundPrice(order.RefundedPrice + refund.Amount)
+	err = tx.Model(&model.Order{}).Where("id = ?", order.Id).Update("refunded_price", refundedPrice).Error
+	if err != nil {
+		return err
+	}
+
+	// 原路退回的退款只记录流水，不增加余额
+	_, err = NewBillService().PostWithTx(tx, dto.BillPostDTO{
+		Uid:      order.Uid,
+		PM:       model.BillPMIncome,
+		Title:    "订单退款",
+		Category: model.BillCategoryPay,
+		Type:     model.BillTypePayRefund,
+		Number:   refund.Amount,
+		LinkId:   refund.RefundNo,
+		Mark:     "订单" + order.OrderNo + "退款",
+	})
+	if err != nil {
+		return err
+	}
+	if order.Status == model.OrderStatusCancelled {
+		return nil
+	}
+
+	event := model.OrderEventPartRefund
+	if refundedPrice >= order.PayPrice {
+		event = model.OrderEventRefund
+	}
+	_, err = NewOrderService().TransitionWithTx(tx, dto.OrderTransitionDTO{
+		OrderId:   order.Id,
+		Event:     event,
+		ActorType: refund.ActorType,
+		ActorId:   refund.ActorId,
+		Reason:    "退款单" + refund.RefundNo + "退款成功",
+	})
+	return err
+}
+
+// 退款失败，订单保持原退款状态，可以重新同意退款
+func (p *RefundService) Fail(refundNo string, message string) error {
+	return db.Client.Model(&model.OrderRefund{}).
+		Where("refund_no = ? AND status = ?", refundNo, model.OrderRefundStatusProcessing).
+		Updates(map[string]interface{}{
+			"status": model.OrderRefundStatusFailed,
+			"error":  message,
+		}).Error
+}
+
+// 拒绝退款
+func (p *RefundService) Reject(order model.Order, adminId int, reason string) (model.Order, error) {
+	if reason == "" {
+		return order, errors.New("请填写拒绝原因")
+	}
+
+	return NewOrderService().Transition(dto.OrderTransitionDTO{
+		OrderId:   order.Id,
+		Event:     model.OrderEventRejectRefund,
+		ActorType: model.OrderActorAdmin,
+		ActorId:   adminId,
+		Reason:    reason,
+		Data: map[string]interface{}{
+			"refund_rejection_reason": reason,
+		},
+	})
+}
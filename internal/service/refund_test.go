@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+func TestRefundCreateWithTx(t *testing.T) {
+	setupDB(t, &model.Order{}, &model.OrderRefund{})
+	order := model.Order{OrderNo: "R001", PayPrice: 19.99, Paid: 1, Status: model.OrderStatusPaid}
+	if err := db.Client.Create(&order).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	refund, err := NewRefundService().CreateWithTx(db.Client, order.Id, 5, "", model.OrderActorAdmin, 1)
+	if err != nil || refund.RefundNo != "R001R1" {
+		t.Fatalf("unexpected refund %+v: %v", refund, err)
+	}
+
+	// 金额相同时返回退款中的退款单
+	again, err := NewRefundService().CreateWithTx(db.Client, order.Id, 5, "", model.OrderActorAdmin, 1)
+	if err != nil || again.Id != refund.Id {
+		t.Fatalf("expected the processing refund, got %+v: %v", again, err)
+	}
+
+	// 金额不同时不能发起
+	if _, err = NewRefundService().CreateWithTx(db.Client, order.Id, 8, "", model.OrderActorAdmin, 1); err == nil {
+		t.Fatal("expected a different amount to fail")
+	}
+	var count int64
+	db.Client.Model(&model.OrderRefund{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected 1 refund, got %d", count)
+	}
+}
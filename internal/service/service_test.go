@@ -0,0 +1,51 @@
+package service
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	appmodel "github.com/quarkcloudio/quark-go/v3/model"
+	_ "github.com/quarkcloudio/quark-smart/v2/internal/testenv"
+	"gorm.io/gorm"
+)
+
+// sqlite 没有 MySQL 的 GREATEST 函数，注册两个参数的版本
+func init() {
+	gosqlite.MustRegisterDeterministicScalarFunction("greatest", 2, func(ctx *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if toFloat(args[0]) >= toFloat(args[1]) {
+			return args[0], nil
+		}
+		return args[1], nil
+	})
+}
+
+func toFloat(value driver.Value) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// 使用内存数据库，迁移配置表和指定的模型
+func setupDB(t *testing.T, models ...interface{}) {
+	t.Helper()
+
+	client, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := client.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err = client.AutoMigrate(append([]interface{}{&appmodel.Config{}}, models...)...); err != nil {
+		t.Fatal(err)
+	}
+	db.Client = client
+}
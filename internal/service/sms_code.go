@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/redis"
+	"github.com/quarkcloudio/quark-smart/v2/config"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/sms"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+// 短信验证码场景
+const (
+	SmsSceneLogin    = "login"    // 登录
+	SmsSceneRegister = "register" // 注册
+	SmsSceneBind     = "bind"     // 绑定手机号
+	SmsSceneDelete   = "delete"   // 注销账号
+)
+
+// 短信验证码规则
+const (
+	SmsCodeLength      = 6               // 验证码长度
+	SmsCodeTTL         = 5 * time.Minute // 验证码有效期
+	SmsCodeInterval    = time.Minute     // 同一手机号发送间隔
+	SmsPhoneDailyLimit = 10              // 同一手机号每天发送次数
+	SmsIpDailyLimit    = 50              // 同一IP每天发送次数
+	SmsCodeMaxAttempts = 5               // 同一验证码最多校验次数
+)
+
+type SmsCodeService struct {
+	store smsCodeStore
+}
+
+func NewSmsCodeService() *SmsCodeService {
+	var store smsCodeStore = &dbSmsCodeStore{}
+	if redis.Client != nil {
+		store = &redisSmsCodeStore{}
+	}
+	return &SmsCodeService{store: store}
+}
+
+// 发送验证码，保存验证码摘要后直接发送，明文验证码不写入队列等任何存储
+func (p *SmsCodeService) Send(ctx context.Context, phone string, scene string, ip string) error {
+	if err := sms.CheckPhone(phone); err != nil {
+		return err
+	}
+	if !p.isScene(scene) {
+		return errors.New("验证码场景错误")
+	}
+	if config.App.Key == "" {
+		return errors.New("未配置应用密钥APP_KEY，不能发送验证码")
+	}
+	code, err := p.makeCode()
+	if err != nil {
+		return err
+	}
+	if err := p.store.save(phone, scene, p.hash(phone, scene, code), ip); err != nil {
+		return err
+	}
+
+	return p.SendCode(ctx, phone, code)
+}
+
+// 校验验证码，校验通过后验证码失效
+func (p *SmsCodeService) Verify(phone string, scene string, code string) error {
+	if phone == "" || code == "" {
+		return errors.New("请填写手机号和验证码")
+	}
+	if config.App.Key == "" {
+		return errors.New("未配置应用密钥APP_KEY，不能校验验证码")
+	}
+	return p.store.verify(phone, scene, p.hash(phone, scene, strings.TrimSpace(code)))
+}
+
+// 通过配置的短信服务商发送验证码
+func (p *SmsCodeService) SendCode(ctx context.Context, phone string, code string) error {
+	sender, err := sms.GetFailoverSender(strings.Split(p.GetDriver(), ",")...)
+	if err != nil {
+		return err
+	}
+
+	content := utils.GetConfig("SMS_CODE_CONTENT")
+	if content == "" {
+		content = "您的验证码是{code}，5分钟内有效"
+	}
+	return sender.Send(ctx, sms.Message{
+		Phone:   phone,
+		Params:  map[string]string{"code": code},
+		Content: strings.ReplaceAll(content, "{code}", code),
+	})
+}
+
+// 获取当前配置的短信服务商，多个服务商用逗号分隔，按顺序故障转移，默认为阿里云短信
+func (p *SmsCodeService) GetDriver() string {
+	driver := utils.GetConfig("SMS_DRIVER")
+	if driver == "" {
+		driver = sms.ProviderAliyun
+	}
+	return driver
+}
+
+// 是否为支持的验证码场景
+func (p *SmsCodeService) isScene(scene string) bool {
+	switch scene {
+	case SmsSceneLogin, SmsSceneRegister, SmsSceneBind, SmsSceneDelete:
+		return true
+	}
+	return false
+}
+
+// 生成数字验证码
+func (p *SmsCodeService) makeCode() (string, error) {
+	code := ""
+	for i := 0; i < SmsCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code += n.String()
+	}
+	return code, nil
+}
+
+// 验证码摘要，使用应用密钥签名，数据泄露时无法还原验证码，应用密钥为空时不能使用
+func (p *SmsCodeService) hash(phone string, scene string, code string) string {
+	mac := hmac.New(sha256.New, []byte(config.App.Key))
+	mac.Write([]byte(phone + ":" + scene + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/dal/redis"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 短信验证码存储
+type smsCodeStore interface {
+	save(phone string, scene string, hash string, ip string) error // 检查发送频率后保存验证码并计数，超过限制时返回错误，覆盖同手机号同场景的旧验证码
+	verify(phone string, scene string, hash string) error          // 校验验证码，通过后作废
+}
+
+// 验证码比较
+func smsCodeEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Redis 存储
+type redisSmsCodeStore struct{}
+
+// 检查发送频率、保存验证码和计数在同一脚本中执行，保存成功后才计入发送次数
+//
+// KEYS：验证码、发送间隔、手机号计数、IP计数（可选）；返回-1表示发送太频繁，-2表示手机号超过每日上限，-3表示IP超过每日上限
+const smsCodeSaveScript = `
+if redis.call("exists", KEYS[2]) == 1 then
+	return -1
+end
+if tonumber(redis.call("get", KEYS[3]) or "0") >= tonumber(ARGV[4]) then
+	return -2
+end
+if KEYS[4] and tonumber(redis.call("get", KEYS[4]) or "0") >= tonumber(ARGV[5]) then
+	return -3
+end
+redis.call("del", KEYS[1])
+redis.call("hset", KEYS[1], "hash", ARGV[1], "attempts", 0)
+redis.call("pexpire", KEYS[1], ARGV[2])
+redis.call("set", KEYS[2], 1, "PX", ARGV[3])
+for i = 3, #KEYS do
+	if redis.call("incr", KEYS[i]) == 1 then
+		redis.call("expire", KEYS[i], 86400)
+	end
+end
+return 0
+`
+
+// 保存验证码
+func (p *redisSmsCodeStore) save(phone string, scene string, hash string, ip string) error {
+	date := time.Now().Format("20060102")
+	keys := []string{"sms:code:" + scene + ":" + phone, "sms:interval:" + phone, "sms:count:phone:" + phone + ":" + date}
+	if ip != "" {
+		keys = append(keys, "sms:count:ip:"+ip+":"+date)
+	}
+
+	result, err := redis.Client.Eval(context.Background(), smsCodeSaveScript, keys,
+		hash, SmsCodeTTL.Milliseconds(), SmsCodeInterval.Milliseconds(), SmsPhoneDailyLimit, SmsIpDailyLimit).Int()
+	if err != nil {
+		return err
+	}
+	return smsCodeSaveError(result)
+}
+
+// 发送频率限制错误
+func smsCodeSaveError(result int) error {
+	switch result {
+	case -1:
+		return errors.New("发送太频繁，请稍后再试")
+	case -2:
+		return errors.New("该手机号今日发送次数已达上限")
+	case -3:
+		return errors.New("今日发送次数已达上限")
+	}
+	return nil
+}
+
+// 校验验证码，检查、计数和作废在同一脚本中执行，并发校验时不会重复通过，
+// 验证码过期后不会因计数重新创建没有过期时间的键
+//
+// 返回-1表示验证码已失效，-2表示错误次数过多，0表示校验通过，大于0为已尝试次数
+const smsCodeVerifyScript = `
+local stored = redis.call("hget", KEYS[1], "hash")
+if not stored then
+	return -1
+end
+local attempts = redis.call("hincrby", KEYS[1], "attempts", 1)
+if attempts > tonumber(ARGV[2]) then
+	redis.call("del", KEYS[1])
+	return -2
+end
+if stored == ARGV[1] then
+	redis.call("del", KEYS[1])
+	return 0
+end
+return attempts
+`
+
+// 校验验证码
+func (p *redisSmsCodeStore) verify(phone string, scene string, hash string) error {
+	ctx := context.Background()
+	key := "sms:code:" + scene + ":" + phone
+
+	result, err := redis.Client.Eval(ctx, smsCodeVerifyScript, []string{key}, hash, SmsCodeMaxAttempts).Int()
+	if err != nil {
+		return err
+	}
+	switch {
+	case result == -1:
+		return errors.New("验证码已失效，请重新获取")
+	case result == -2:
+		return errors.New("验证码错误次数过多，请重新获取")
+	case result > 0:
+		return errors.New("验证码错误，还可以尝试" + strconv.Itoa(SmsCodeMaxAttempts-result) + "次")
+	}
+	return nil
+}
+
+// 数据库存储
+type dbSmsCodeStore struct{}
+
+// 保存验证码，旧验证码在校验时按最新一条处理，无需删除
+//
+// 手机号和IP的计数行加锁后检查发送频率，与保存验证码在同一事务中完成，并发请求不会超过限制
+func (p *dbSmsCodeStore) save(phone string, scene string, hash string, ip string) error {
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		today := now.Format(time.DateOnly)
+
+		phoneCounter, err := p.lockCounter(tx, "phone:"+phone, today)
+		if err != nil {
+			return err
+		}
+		if phoneCounter.SentAt.Time.After(now.Add(-SmsCodeInterval)) {
+			return smsCodeSaveError(-1)
+		}
+		if phoneCounter.Count >= SmsPhoneDailyLimit {
+			return smsCodeSaveError(-2)
+		}
+		counters := []model.SmsCounter{phoneCounter}
+		if ip != "" {
+			ipCounter, err := p.lockCounter(tx, "ip:"+ip, today)
+			if err != nil {
+				return err
+			}
+			if ipCounter.Count >= SmsIpDailyLimit {
+				return smsCodeSaveError(-3)
+			}
+			counters = append(counters, ipCounter)
+		}
+
+		err = tx.Create(&model.SmsCode{
+			Phone:    phone,
+			Scene:    scene,
+			CodeHash: hash,
+			Ip:       ip,
+			ExpireAt: datetime.Datetime{Time: now.Add(SmsCodeTTL)},
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, counter := range counters {
+			err = tx.Model(&model.SmsCounter{}).Where("name = ?", counter.Name).Updates(map[string]interface{}{
+				"day":     today,
+				"count":   counter.Count + 1,
+				"sent_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 锁定计数行，行不存在时先创建，不是当天的计数按0处理
+func (p *dbSmsCodeStore) lockCounter(tx *gorm.DB, name string, today string) (model.SmsCounter, error) {
+	counter := model.SmsCounter{}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.SmsCounter{Name: name, Day: today}).Error
+	if err != nil {
+		return counter, err
+	}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&counter).Error
+	if counter.Day != today {
+		counter.Count = 0
+	}
+	return counter, err
+}
+
+// 校验验证码
+func (p *dbSmsCodeStore) verify(phone string, scene string, hash string) error {
+	smsCode := model.SmsCode{}
+	err := db.Client.
+		Where("phone = ?", phone).
+		Where("scene = ?", scene).
+		Order("id desc").
+		First(&smsCode).Error
+	if err != nil || smsCode.Status != 0 || smsCode.ExpireAt.Time.Before(time.Now()) {
+		return errors.New("验证码已失效，请重新获取")
+	}
+
+	// 条件更新保证并发校验时次数准确
+	result := db.Client.Model(&model.SmsCode{}).
+		Where("id = ?", smsCode.Id).
+		Where("status = ?", 0).
+		Where("attempts < ?", SmsCodeMaxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("验证码错误次数过多，请重新获取")
+	}
+	if !smsCodeEqual(smsCode.CodeHash, hash) {
+		return errors.New("验证码错误，还可以尝试" + strconv.Itoa(SmsCodeMaxAttempts-smsCode.Attempts-1) + "次")
+	}
+
+	result = db.Client.Model(&model.SmsCode{}).Where("id = ?", smsCode.Id).Where("status = ?", 0).Update("status", 1)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("验证码已失效，请重新获取")
+	}
+	return nil
+}
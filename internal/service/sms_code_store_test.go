@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+func TestDbSmsCodeStoreLimits(t *testing.T) {
+	setupDB(t, &model.SmsCode{}, &model.SmsCounter{})
+	store := &dbSmsCodeStore{}
+
+	if err := store.save("13800000000", SmsSceneLogin, "hash", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 发送间隔内不能重复发送，也不计数
+	if err := store.save("13800000000", SmsSceneLogin, "hash", "127.0.0.1"); err == nil {
+		t.Fatal("expected interval error")
+	}
+	counter := model.SmsCounter{}
+	db.Client.Where("name = ?", "phone:13800000000").First(&counter)
+	if counter.Count != 1 {
+		t.Fatalf("expected 1 send, got %d", counter.Count)
+	}
+
+	// 达到每日上限后拒绝发送
+	db.Client.Model(&model.SmsCounter{}).Where("name = ?", "phone:13800000000").Updates(map[string]interface{}{
+		"count":   SmsPhoneDailyLimit,
+		"sent_at": time.Now().Add(-time.Hour),
+	})
+	if err := store.save("13800000000", SmsSceneLogin, "hash", "127.0.0.1"); err == nil {
+		t.Fatal("expected phone daily limit error")
+	}
+
+	// 前一天的计数不影响当天发送
+	db.Client.Model(&model.SmsCounter{}).Where("name = ?", "phone:13800000000").Update("day", "2000-01-01")
+	if err := store.save("13800000000", SmsSceneLogin, "hash", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	db.Client.Where("name = ?", "phone:13800000000").First(&counter)
+	if counter.Count != 1 || counter.Day != time.Now().Format(time.DateOnly) {
+		t.Fatalf("counter should restart today: %+v", counter)
+	}
+
+	var count int64
+	db.Client.Model(&model.SmsCode{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("expected 2 codes, got %d", count)
+	}
+}
+
+func TestDbSmsCodeStoreIpLimit(t *testing.T) {
+	setupDB(t, &model.SmsCode{}, &model.SmsCounter{})
+	store := &dbSmsCodeStore{}
+
+	for i := 0; i < SmsIpDailyLimit; i++ {
+		if err := store.save("1380000"+strconv.Itoa(1000+i), SmsSceneLogin, "hash", "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.save("13900000000", SmsSceneLogin, "hash", "10.0.0.1"); err == nil {
+		t.Fatal("expected ip daily limit error")
+	}
+
+	// 被拒绝的发送不计入手机号次数
+	counter := model.SmsCounter{}
+	db.Client.Where("name = ?", "phone:13900000000").First(&counter)
+	if counter.Count != 0 {
+		t.Fatalf("rejected send should not be counted: %+v", counter)
+	}
+}
+
+func TestDbSmsCodeStoreVerify(t *testing.T) {
+	setupDB(t, &model.SmsCode{}, &model.SmsCounter{})
+	store := &dbSmsCodeStore{}
+	store.save("13800000000", SmsSceneLogin, "hash", "")
+
+	if err := store.verify("13800000000", SmsSceneLogin, "wrong"); err == nil {
+		t.Fatal("expected wrong code error")
+	}
+	if err := store.verify("13800000000", SmsSceneLogin, "hash"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.verify("13800000000", SmsSceneLogin, "hash"); err == nil {
+		t.Fatal("code should be consumed")
+	}
+}
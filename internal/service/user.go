@@ -1,13 +1,23 @@
 package service
 
 import (
+	"errors"
+	"unicode"
+
 	"github.com/quarkcloudio/quark-go/v3/dal/db"
 	"github.com/quarkcloudio/quark-go/v3/model"
 	appservice "github.com/quarkcloudio/quark-go/v3/service"
 	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
 	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/sms"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/wechat"
 )
 
+// 占位值前缀，用户名、邮箱、手机号在用户表中唯一且不能为空，未填写时使用占位值
+const UserPlaceholderPrefix = "#"
+
 type UserService struct{}
 
 func NewUserService() *UserService {
@@ -35,8 +45,118 @@ func (p *UserService) GetInfoByWxOpenid(wxOpenid string) (user model.User) {
 	return user
 }
 
-// 新增用户
+// 通过手机号获取用户信息
+func (p *UserService) GetInfoByPhone(phone string) (user model.User, err error) {
+	err = db.Client.Where("phone = ?", phone).First(&user).Error
+	return user, err
+}
+
+// 用户名是否已存在，包含已删除的用户
+func (p *UserService) IsUsernameExist(username string) bool {
+	var count int64
+	db.Client.Unscoped().Model(model.User{}).Where("username = ?", username).Count(&count)
+	return count > 0
+}
+
+// 手机号是否已存在，包含已删除的用户
+func (p *UserService) IsPhoneExist(phone string) bool {
+	var count int64
+	db.Client.Unscoped().Model(model.User{}).Where("phone = ?", phone).Count(&count)
+	return count > 0
+}
+
+// 校验用户名，4到20位字母、数字或下划线，以字母开头
+func (p *UserService) CheckUsername(username string) error {
+	if !utils.CheckRegex(`^[a-zA-Z][a-zA-Z0-9_]{3,19}$`, username) {
+		return errors.New("用户名为4到20位字母、数字或下划线，且以字母开头")
+	}
+	return nil
+}
+
+// 校验密码强度，8到32位，至少包含字母和数字
+func (p *UserService) CheckPassword(password string) error {
+	if len(password) < 8 || len(password) > 32 {
+		return errors.New("密码长度为8到32位")
+	}
+
+	var hasLetter, hasNumber bool
+	for _, char := range password {
+		switch {
+		case unicode.IsLetter(char):
+			hasLetter = true
+		case unicode.IsDigit(char):
+			hasNumber = true
+		case unicode.IsSpace(char):
+			return errors.New("密码不能包含空格")
+		}
+	}
+	if !hasLetter || !hasNumber {
+		return errors.New("密码至少包含字母和数字")
+	}
+	return nil
+}
+
+// 通过微信手机号快速验证组件返回的 code 绑定手机号
+func (p *UserService) BindWechatPhone(uid int, code string) (phone string, err error) {
+	phone, err = wechat.NewWechatMiniProgram().GetPhoneNumber(code)
+	if err != nil {
+		return phone, err
+	}
+	if err := sms.CheckPhone(phone); err != nil {
+		return phone, err
+	}
+
+	return phone, p.bindPhone(uid, phone, nil)
+}
+
+// 通过短信验证码绑定手机号，手机号未变更时无需验证码
+func (p *UserService) BindPhone(uid int, phone string, code string) error {
+	return p.bindPhone(uid, phone, func() error {
+		if code == "" {
+			return errors.New("请填写短信验证码")
+		}
+		return NewSmsCodeService().Verify(phone, SmsSceneBind, code)
+	})
+}
+
+// 绑定手机号，手机号未被其他账号使用时执行验证后更新
+func (p *UserService) bindPhone(uid int, phone string, verify func() error) error {
+	user, err := p.GetInfoByPhone(phone)
+	if err == nil {
+		if user.Id == uid {
+			return nil
+		}
+		return errors.New("手机号已绑定其他账号")
+	}
+	if p.IsPhoneExist(phone) {
+		return errors.New("手机号已被注册")
+	}
+	if verify != nil {
+		if err := verify(); err != nil {
+			return err
+		}
+	}
+
+	return db.Client.Model(model.User{}).Where("id = ?", uid).Update("phone", phone).Error
+}
+
+// 是否为占位值
+func (p *UserService) IsPlaceholder(value string) bool {
+	return len(value) > 0 && value[:1] == UserPlaceholderPrefix
+}
+
+// 新增用户，未填写的用户名、邮箱、手机号使用占位值
 func (p *UserService) CreateUser(param dto.SaveUserDTO) (model.User, error) {
+	if param.Username == "" {
+		param.Username = UserPlaceholderPrefix + rand.MakeAlphanumeric(16)
+	}
+	if param.Email == "" {
+		param.Email = UserPlaceholderPrefix + rand.MakeAlphanumeric(32)
+	}
+	if param.Phone == "" {
+		param.Phone = UserPlaceholderPrefix + rand.MakeNumeric(10)
+	}
+
 	user := model.User{
 		Username:      param.Username,
 		Nickname:      param.Nickname,
@@ -86,7 +206,7 @@ func (p *UserService) UpdateUser(param dto.SaveUserDTO) (model.User, error) {
 	return user, nil
 }
 
-// 删除用户
-func (p *UserService) DeleteUser(id int) error {
-	return db.Client.Model(model.User{}).Where("id = ?", id).Delete(&model.User{}).Error
+// 更新密码，password 为加密后的密码
+func (p *UserService) UpdatePassword(uid int, password string) error {
+	return db.Client.Model(model.User{}).Where("id = ?", uid).Update("password", password).Error
 }
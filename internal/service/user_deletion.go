@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	quarkmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 注销冷静期默认天数
+const UserDeletionGraceDays = 7
+
+// 注销后的用户昵称
+const UserDeletedNickname = "已注销用户"
+
+type UserDeletionService struct{}
+
+func NewUserDeletionService() *UserDeletionService {
+	return &UserDeletionService{}
+}
+
+// 获取注销冷静期，可通过配置项 USER_DELETION_GRACE_DAYS 设置，单位天
+func (p *UserDeletionService) GetGracePeriod() time.Duration {
+	days, err := strconv.Atoi(utils.GetConfig("USER_DELETION_GRACE_DAYS"))
+	if err != nil || days < 0 {
+		days = UserDeletionGraceDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// 获取用户冷静期中的注销申请
+func (p *UserDeletionService) GetPendingByUid(uid int) (deletion model.UserDeletion, err error) {
+	err = db.Client.
+		Where("uid = ? AND status = ?", uid, model.UserDeletionStatusPending).
+		First(&deletion).Error
+	return deletion, err
+}
+
+// 申请注销，进入冷静期并退出所有设备，冷静期内重新登录即撤销注销
+func (p *UserDeletionService) Apply(uid int, reason string) (deletion model.UserDeletion, err error) {
+	if _, err := p.GetPendingByUid(uid); err == nil {
+		return deletion, errors.New("已申请注销，请勿重复提交")
+	}
+	if err := p.checkUnfinished(uid); err != nil {
+		return deletion, err
+	}
+
+	deletion = model.UserDeletion{
+		Uid:         uid,
+		Reason:      reason,
+		Status:      model.UserDeletionStatusPending,
+		ScheduledAt: datetime.Datetime{Time: time.Now().Add(p.GetGracePeriod())},
+	}
+	if err := db.Client.Create(&deletion).Error; err != nil {
+		return deletion, err
+	}
+	return deletion, NewUserSessionService().RevokeAll(uid)
+}
+
+// 撤销冷静期中的注销申请，没有申请时不做处理
+func (p *UserDeletionService) Cancel(uid int) error {
+	return db.Client.Model(&model.UserDeletion{}).
+		Where("uid = ? AND status = ?", uid, model.UserDeletionStatusPending).
+		Update("status", model.UserDeletionStatusCancelled).Error
+}
+
+// 完成冷静期已结束的注销申请，返回处理数量
+func (p *UserDeletionService) FinalizeDue(ctx context.Context) (count int, err error) {
+	deletions := []model.UserDeletion{}
+	err = db.Client.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", model.UserDeletionStatusPending, time.Now()).
+		Order("id asc").
+		Find(&deletions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, deletion := range deletions {
+		if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		if err := p.finalize(ctx, deletion.Id); err != nil {
+			return count, fmt.Errorf("注销用户%d错误：%w", deletion.Uid, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// 完成注销，匿名化用户信息
+//
+// 用户记录保留并禁用，订单、账单、文章等数据仍可关联；订单中的收货信息作为交易凭证保留
+func (p *UserDeletionService) finalize(ctx context.Context, id int) error {
+	uid := 0
+	err := db.Client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletion := model.UserDeletion{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", id, model.UserDeletionStatusPending).
+			First(&deletion).Error
+		if err != nil {
+			// 已撤销或已处理
+			return nil
+		}
+		uid = deletion.Uid
+
+		// 占位值以用户ID区分，保证唯一，手机号占位值不会与随机生成的数字占位值冲突
+		err = tx.Model(&quarkmodel.User{}).Where("id = ?", uid).Updates(map[string]interface{}{
+			"username":      UserPlaceholderPrefix + "deleted" + strconv.Itoa(uid),
+			"nickname":      UserDeletedNickname,
+			"email":         UserPlaceholderPrefix + "deleted" + strconv.Itoa(uid),
+			"phone":         fmt.Sprintf("%sd%09d", UserPlaceholderPrefix, uid),
+			"password":      "",
+			"avatar":        "",
+			"sex":           0,
+			"wx_openid":     "",
+			"wx_unionid":    "",
+			"last_login_ip": "",
+			"status":        0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("uid = ?", uid).Delete(&model.UserWechat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("uid = ?", uid).Delete(&model.UserAddress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("uid = ?", uid).Delete(&model.Cart{}).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&model.Clerk{}).Where("uid = ?", uid).Updates(map[string]interface{}{
+			"phone":  nil,
+			"status": 0,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.UserDeletion{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":       model.UserDeletionStatusCompleted,
+			"completed_at": datetime.Now(),
+		}).Error
+	})
+	if err != nil || uid == 0 {
+		return err
+	}
+	return NewUserSessionService().RevokeAll(uid)
+}
+
+// 检查是否有未完成的订单，有未完成的订单时不能注销
+func (p *UserDeletionService) checkUnfinished(uid int) error {
+	var count int64
+	db.Client.Model(&model.Order{}).
+		Where("uid = ?", uid).
+		Where(db.Client.
+			Where("status IN ?", []model.OrderStatus{
+				model.OrderStatusPaid,
+				model.OrderStatusShipped,
+				model.OrderStatusAwaitingPickup,
+			}).
+			Or("refund_status = ?", model.RefundStatusApplying)).
+		Count(&count)
+	if count > 0 {
+		return errors.New("您有未完成的订单，请完成后再注销")
+	}
+	return nil
+}
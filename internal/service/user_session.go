@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/dal/redis"
+	"github.com/quarkcloudio/quark-go/v3/utils/datetime"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 令牌默认有效期
+const (
+	AccessTokenTTL  = 2 * time.Hour       // 访问令牌有效期
+	RefreshTokenTTL = 30 * 24 * time.Hour // 刷新令牌有效期
+)
+
+// 刷新令牌失效
+var ErrRefreshTokenInvalid = errors.New("登录已失效，请重新登录")
+
+type UserSessionService struct{}
+
+func NewUserSessionService() *UserSessionService {
+	return &UserSessionService{}
+}
+
+// 获取访问令牌有效期，可通过配置项 AUTH_ACCESS_TOKEN_TTL 设置，单位分钟
+func (p *UserSessionService) GetAccessTokenTTL() time.Duration {
+	minutes, err := strconv.Atoi(utils.GetConfig("AUTH_ACCESS_TOKEN_TTL"))
+	if err != nil || minutes <= 0 {
+		return AccessTokenTTL
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// 获取刷新令牌有效期，可通过配置项 AUTH_REFRESH_TOKEN_TTL 设置，单位天
+func (p *UserSessionService) GetRefreshTokenTTL() time.Duration {
+	days, err := strconv.Atoi(utils.GetConfig("AUTH_REFRESH_TOKEN_TTL"))
+	if err != nil || days <= 0 {
+		return RefreshTokenTTL
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// 通过ID获取会话
+func (p *UserSessionService) GetInfoById(id interface{}) (session model.UserSession, err error) {
+	err = db.Client.Where("id = ?", id).First(&session).Error
+	return session, err
+}
+
+// 创建会话，返回会话和刷新令牌
+func (p *UserSessionService) Create(uid int, ip string, userAgent string) (session model.UserSession, refreshToken string, err error) {
+	refreshToken, err = p.makeRefreshToken()
+	if err != nil {
+		return session, refreshToken, err
+	}
+
+	now := time.Now()
+	session = model.UserSession{
+		Uid:              uid,
+		RefreshTokenHash: p.hash(refreshToken),
+		Ip:               ip,
+		UserAgent:        p.truncate(userAgent, 500),
+		Status:           model.UserSessionStatusActive,
+		ExpireAt:         datetime.Datetime{Time: now.Add(p.GetRefreshTokenTTL())},
+		RefreshedAt:      datetime.Datetime{Time: now},
+	}
+	err = db.Client.Create(&session).Error
+	return session, refreshToken, err
+}
+
+// 轮换刷新令牌，旧令牌立即作废
+//
+// 已轮换的旧令牌再次使用说明令牌可能泄露，此时注销整个会话
+func (p *UserSessionService) Rotate(refreshToken string, ip string) (session model.UserSession, newRefreshToken string, err error) {
+	if refreshToken == "" {
+		return session, newRefreshToken, ErrRefreshTokenInvalid
+	}
+	newRefreshToken, err = p.makeRefreshToken()
+	if err != nil {
+		return session, newRefreshToken, err
+	}
+
+	hash := p.hash(refreshToken)
+	reused := false
+	err = db.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("refresh_token_hash = ?", hash).First(&session).Error
+		if err != nil {
+			err = tx.Where("prev_refresh_token_hash = ?", hash).First(&session).Error
+			if err == nil {
+				reused = true
+			}
+			return ErrRefreshTokenInvalid
+		}
+		if session.Status != model.UserSessionStatusActive || time.Now().After(session.ExpireAt.Time) {
+			return ErrRefreshTokenInvalid
+		}
+
+		now := time.Now()
+		session.PrevRefreshTokenHash = session.RefreshTokenHash
+		session.RefreshTokenHash = p.hash(newRefreshToken)
+		session.Ip = ip
+		session.ExpireAt = datetime.Datetime{Time: now.Add(p.GetRefreshTokenTTL())}
+		session.RefreshedAt = datetime.Datetime{Time: now}
+		return tx.Model(&model.UserSession{}).Where("id = ?", session.Id).Updates(map[string]interface{}{
+			"prev_refresh_token_hash": session.PrevRefreshTokenHash,
+			"refresh_token_hash":      session.RefreshTokenHash,
+			"ip":                      session.Ip,
+			"expire_at":               session.ExpireAt,
+			"refreshed_at":            session.RefreshedAt,
+		}).Error
+	})
+	if reused {
+		p.Revoke(session.Id)
+	}
+	return session, newRefreshToken, err
+}
+
+// 注销会话，会话签发的访问令牌同时失效
+func (p *UserSessionService) Revoke(id int) error {
+	err := db.Client.Model(&model.UserSession{}).
+		Where("id = ?", id).
+		Update("status", model.UserSessionStatusRevoked).Error
+	if err != nil {
+		return err
+	}
+	return p.addRevoked(id)
+}
+
+// 注销用户的全部会话，用于退出所有设备
+func (p *UserSessionService) RevokeAll(uid int) error {
+	ids := []int{}
+	err := db.Client.Model(&model.UserSession{}).
+		Where("uid = ? AND status = ?", uid, model.UserSessionStatusActive).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	err = db.Client.Model(&model.UserSession{}).
+		Where("id IN ?", ids).
+		Update("status", model.UserSessionStatusRevoked).Error
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := p.addRevoked(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 会话是否已注销，配置 Redis 时查询注销列表，否则查询会话状态
+func (p *UserSessionService) IsRevoked(id int) bool {
+	if redis.Client != nil {
+		count, err := redis.Client.Exists(context.Background(), p.revokedKey(id)).Result()
+		return err != nil || count > 0
+	}
+
+	session, err := p.GetInfoById(id)
+	return err != nil || session.Status != model.UserSessionStatusActive
+}
+
+// 清理已过期的会话
+func (p *UserSessionService) PruneExpired(ctx context.Context, before time.Time) error {
+	return db.Client.WithContext(ctx).Where("expire_at < ?", before).Delete(&model.UserSession{}).Error
+}
+
+// 加入注销列表，保留到访问令牌过期为止
+func (p *UserSessionService) addRevoked(id int) error {
+	if redis.Client == nil {
+		return nil
+	}
+	return redis.Client.Set(context.Background(), p.revokedKey(id), 1, p.GetAccessTokenTTL()).Err()
+}
+
+// 注销列表的键名
+func (p *UserSessionService) revokedKey(id int) string {
+	return "auth:revoked:" + strconv.Itoa(id)
+}
+
+// 生成刷新令牌
+func (p *UserSessionService) makeRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// 刷新令牌摘要，数据库中只保存摘要
+func (p *UserSessionService) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// 按字符截断
+func (p *UserSessionService) truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	quarkmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserWechatService struct{}
+
+func NewUserWechatService() *UserWechatService {
+	return &UserWechatService{}
+}
+
+// 获取用户绑定的微信身份
+func (p *UserWechatService) GetListByUid(uid int) (list []model.UserWechat, err error) {
+	err = db.Client.Where("uid = ? AND status = ?", uid, 1).Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// 获取用户在指定平台的 openid，未绑定时返回空字符串
+func (p *UserWechatService) GetOpenid(uid int, platform string) string {
+	userWechat := model.UserWechat{}
+	db.Client.Where("uid = ? AND platform = ? AND status = ?", uid, platform, 1).First(&userWechat)
+	if userWechat.Openid != "" || platform != model.WechatPlatformMP {
+		return userWechat.Openid
+	}
+
+	// 兼容仅记录在用户表中的小程序 openid
+	user, _ := NewUserService().GetInfoById(uid)
+	return user.WxOpenid
+}
+
+// 查找微信身份所属的用户ID，未找到时返回0
+//
+// 依次通过当前平台 openid、其他平台的 unionid 查找，用于合并同一开放平台下小程序和公众号的用户；
+// 已解绑的身份不再合并，返回0
+func (p *UserWechatService) GetUidByIdentity(identity dto.WechatIdentityDTO) int {
+	userWechat := model.UserWechat{}
+	db.Client.Where("platform = ? AND openid = ?", identity.Platform, identity.Openid).First(&userWechat)
+	if userWechat.Id > 0 && userWechat.Status == 0 {
+		return 0
+	}
+	if userWechat.Uid > 0 {
+		return userWechat.Uid
+	}
+	if identity.Unionid != "" {
+		db.Client.Where("unionid = ? AND status = ?", identity.Unionid, 1).Order("id asc").First(&userWechat)
+		if userWechat.Uid > 0 {
+			return userWechat.Uid
+		}
+	}
+
+	// 兼容绑定表之前仅记录在用户表中的微信身份
+	user := quarkmodel.User{}
+	if identity.Unionid != "" {
+		db.Client.Where("wx_unionid = ?", identity.Unionid).Order("id asc").First(&user)
+	}
+	if user.Id == 0 {
+		db.Client.Where("wx_openid = ?", identity.Openid).Order("id asc").First(&user)
+	}
+	return user.Id
+}
+
+// 保存用户的微信身份，同时同步用户表中的 openid、unionid
+func (p *UserWechatService) Save(uid int, identity dto.WechatIdentityDTO) error {
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		userWechat := model.UserWechat{
+			Uid:      uid,
+			Platform: identity.Platform,
+			Openid:   identity.Openid,
+			Unionid:  identity.Unionid,
+			Nickname: identity.Nickname,
+			Avatar:   identity.Avatar,
+			Status:   1,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "platform"}, {Name: "openid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"uid", "unionid", "status", "updated_at"}),
+		}).Create(&userWechat).Error
+		if err != nil {
+			return err
+		}
+
+		data := map[string]interface{}{}
+		if identity.Platform == model.WechatPlatformMP {
+			data["wx_openid"] = identity.Openid
+		}
+		if identity.Unionid != "" {
+			data["wx_unionid"] = identity.Unionid
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return tx.Model(&quarkmodel.User{}).Where("id = ?", uid).Updates(data).Error
+	})
+}
+
+// 绑定微信，微信已绑定其他账号或账号已绑定其他微信时返回错误
+func (p *UserWechatService) Bind(uid int, identity dto.WechatIdentityDTO) error {
+	if bindUid := p.GetUidByIdentity(identity); bindUid > 0 && bindUid != uid {
+		return errors.New("该微信已绑定其他账号")
+	}
+
+	var count int64
+	db.Client.Model(&model.UserWechat{}).
+		Where("uid = ? AND platform = ? AND openid <> ? AND status = ?", uid, identity.Platform, identity.Openid, 1).
+		Count(&count)
+	if count > 0 {
+		return errors.New("账号已绑定其他微信，请先解绑")
+	}
+
+	return p.Save(uid, identity)
+}
+
+// 解绑微信，解绑后用户需仍可通过手机号或密码登录
+//
+// 解绑的身份保留记录并标记为已解绑，防止下次登录时通过 unionid 重新合并到该用户
+func (p *UserWechatService) Unbind(uid int, platform string) error {
+	userService := NewUserService()
+	user, err := userService.GetInfoById(uid)
+	if err != nil {
+		return err
+	}
+	if userService.IsPlaceholder(user.Phone) && user.Password == "" {
+		return errors.New("请先绑定手机号或设置密码后再解绑")
+	}
+
+	return db.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.UserWechat{}).Where("uid = ? AND platform = ?", uid, platform).Update("status", 0).Error
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		tx.Model(&model.UserWechat{}).Where("uid = ? AND status = ?", uid, 1).Count(&count)
+		data := map[string]interface{}{}
+		if platform == model.WechatPlatformMP {
+			data["wx_openid"] = ""
+		}
+		if count == 0 {
+			data["wx_openid"] = ""
+			data["wx_unionid"] = ""
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return tx.Model(&quarkmodel.User{}).Where("id = ?", uid).Updates(data).Error
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	quarkmodel "github.com/quarkcloudio/quark-go/v3/model"
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/internal/model"
+)
+
+func TestUserWechatUnbind(t *testing.T) {
+	setupDB(t, &quarkmodel.User{}, &model.UserWechat{})
+	users := []quarkmodel.User{
+		{Id: 1, Username: "u1", Email: "u1@example.com", Phone: "13800000001", Status: 1},
+		{Id: 2, Username: "u2", Email: "u2@example.com", Phone: "13800000002", Status: 1},
+	}
+	if err := db.Client.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	mp := dto.WechatIdentityDTO{Platform: model.WechatPlatformMP, Openid: "mp-openid", Unionid: "unionid"}
+	oa := dto.WechatIdentityDTO{Platform: model.WechatPlatformOA, Openid: "oa-openid", Unionid: "unionid"}
+	for _, identity := range []dto.WechatIdentityDTO{mp, oa} {
+		if err := NewUserWechatService().Save(1, identity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := NewUserWechatService().Unbind(1, model.WechatPlatformOA); err != nil {
+		t.Fatal(err)
+	}
+
+	// 解绑的公众号身份不再通过 unionid 合并到原用户，小程序身份不受影响
+	if uid := NewUserWechatService().GetUidByIdentity(oa); uid != 0 {
+		t.Fatalf("unbound identity merged into user %d", uid)
+	}
+	if uid := NewUserWechatService().GetUidByIdentity(mp); uid != 1 {
+		t.Fatalf("expected mp identity to belong to user 1, got %d", uid)
+	}
+	if list, _ := NewUserWechatService().GetListByUid(1); len(list) != 1 || list[0].Platform != model.WechatPlatformMP {
+		t.Fatalf("unexpected identities: %+v", list)
+	}
+
+	// 解绑后可以绑定到其他账号
+	if err := NewUserWechatService().Bind(2, oa); err != nil {
+		t.Fatal(err)
+	}
+	if uid := NewUserWechatService().GetUidByIdentity(oa); uid != 2 {
+		t.Fatalf("expected oa identity to belong to user 2, got %d", uid)
+	}
+}
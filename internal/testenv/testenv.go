@@ -0,0 +1,23 @@
+// 测试环境变量
+//
+// config 包在初始化时读取配置，测试中没有.env文件时需要在 config 初始化前设置环境变量。
+// 测试文件匿名导入本包即可，本包只依赖标准库，导入路径排在 config 依赖的 pkg/env 之前，
+// 按 Go 的包初始化顺序会先于 config 初始化；已设置的环境变量不会被覆盖。
+package testenv
+
+import "os"
+
+var defaults = map[string]string{
+	"APP_PRO":        "false",
+	"APP_ENV":        "test",
+	"APP_KEY":        "test-app-key",
+	"REDIS_PASSWORD": "",
+}
+
+func init() {
+	for key, value := range defaults {
+		if _, ok := os.LookupEnv(key); !ok {
+			os.Setenv(key, value)
+		}
+	}
+}
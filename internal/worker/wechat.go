@@ -0,0 +1,12 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/quarkcloudio/quark-smart/v2/pkg/wechat"
+)
+
+// 发送微信模板消息
+func SendWechatTemplateMessage(ctx context.Context, msg wechat.Message) error {
+	return wechat.NewWechatTemplateMessage().Send(&msg)
+}
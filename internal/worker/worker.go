@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"github.com/quarkcloudio/quark-smart/v2/internal/dto"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/queue"
+)
+
+// 队列工作协程数量
+const Workers = 4
+
+// 注册队列任务处理函数并启动工作协程
+func Start() {
+	q := queue.NewQueue()
+
+	// 发送微信模板消息
+	queue.RegisterFunc(q, dto.TaskWechatTemplateMessage, SendWechatTemplateMessage)
+
+	q.Start(Workers)
+}
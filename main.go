@@ -16,8 +16,10 @@ import (
 	"github.com/quarkcloudio/quark-smart/v2/database"
 	adminEngineService "github.com/quarkcloudio/quark-smart/v2/internal/app/admin/engine"
 	toolEngineService "github.com/quarkcloudio/quark-smart/v2/internal/app/tool/engine"
+	"github.com/quarkcloudio/quark-smart/v2/internal/job"
 	"github.com/quarkcloudio/quark-smart/v2/internal/middleware"
 	"github.com/quarkcloudio/quark-smart/v2/internal/router"
+	"github.com/quarkcloudio/quark-smart/v2/internal/worker"
 	"github.com/quarkcloudio/quark-smart/v2/pkg/env"
 	"github.com/quarkcloudio/quark-smart/v2/pkg/template"
 	"gorm.io/driver/mysql"
@@ -146,10 +148,19 @@ func main() {
 	// 注册Web路由
 	router.WebRegister(b)
 
+	// 启动队列工作协程
+	worker.Start()
+
 	// 开启高级功能
 	if appPro {
 		// 注册MiniApp路由
 		router.MiniAppRegister(b)
+
+		// 注册支付路由
+		router.PayRegister(b)
+
+		// 启动定时任务
+		job.Start()
 	}
 
 	// 启动服务
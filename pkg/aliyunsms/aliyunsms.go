@@ -44,49 +44,59 @@ func (p *App) SendSms(phone string, code string) (bool, string) {
 		return false, "手机号格式错误！"
 	}
 
-	client, _err := Client(tea.String(p.Config.AccessKeyId), tea.String(p.Config.AccessKeySecret))
-	if _err != nil {
-		return false, _err.Error()
+	if _, err := p.Send(phone, p.Config.TemplateCode, "{\"code\":\""+code+"\"}"); err != nil {
+		return false, err.Error()
+	}
+
+	return true, ""
+}
+
+// 发送模板短信，templateParam 为模板参数的 Json 字符串，返回平台的响应结果
+//
+// 请求失败时返回错误，平台返回的 Code 不为 OK 时同样返回错误
+func (p *App) Send(phone string, templateCode string, templateParam string) (body *dysmsapi20170525.SendSmsResponseBody, err error) {
+	defer func() {
+		if r := tea.Recover(recover()); r != nil {
+			err = r
+		}
+	}()
+
+	client, err := Client(tea.String(p.Config.AccessKeyId), tea.String(p.Config.AccessKeySecret))
+	if err != nil {
+		return nil, err
 	}
 
 	sendSmsRequest := &dysmsapi20170525.SendSmsRequest{
 		PhoneNumbers:  tea.String(phone),
 		SignName:      tea.String(p.Config.SignName),
-		TemplateCode:  tea.String(p.Config.TemplateCode),
-		TemplateParam: tea.String("{\"code\":\"" + code + "\"}"),
+		TemplateCode:  tea.String(templateCode),
+		TemplateParam: tea.String(templateParam),
 	}
-	runtime := &util.RuntimeOptions{}
-	tryErr := func() (_e error) {
-		defer func() {
-			if r := tea.Recover(recover()); r != nil {
-				_e = r
-			}
-		}()
-		// 复制代码运行请自行打印 API 的返回值
-		_, _err = client.SendSmsWithOptions(sendSmsRequest, runtime)
-		if _err != nil {
-			return _err
+	response, err := client.SendSmsWithOptions(sendSmsRequest, &util.RuntimeOptions{})
+	if err != nil {
+		if sdkError, ok := err.(*tea.SDKError); ok {
+			return nil, &Error{Code: tea.StringValue(sdkError.Code), Message: tea.StringValue(sdkError.Message)}
 		}
-
-		return nil
-	}()
-
-	if tryErr != nil {
-		var error = &tea.SDKError{}
-		if _t, ok := tryErr.(*tea.SDKError); ok {
-			error = _t
-		} else {
-			error.Message = tea.String(tryErr.Error())
-		}
-		// 如有需要，请打印 error
-		util.AssertAsString(error.Message)
+		return nil, err
 	}
-
-	if _err != nil {
-		return false, _err.Error()
+	if response.Body == nil {
+		return nil, &Error{Code: "EmptyResponse", Message: "短信平台返回为空"}
+	}
+	if tea.StringValue(response.Body.Code) != "OK" {
+		return response.Body, &Error{Code: tea.StringValue(response.Body.Code), Message: tea.StringValue(response.Body.Message)}
 	}
 
-	return true, ""
+	return response.Body, nil
+}
+
+// 短信平台返回的错误
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Code + "：" + e.Message
 }
 
 // 使用AK&SK初始化账号Client
@@ -2,6 +2,7 @@ package env
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/viper"
 )
@@ -18,26 +19,20 @@ func Set(key string, value interface{}) {
 	}
 }
 
-// 获取值
+// 获取值，优先读取.env文件，其次读取环境变量，都没有时返回默认值
 func Get(key ...string) interface{} {
 	viper.SetConfigFile(".env")
-	err := viper.ReadInConfig()
+	viper.ReadInConfig()
 
-	if err != nil {
-		if len(key) == 2 {
-			if viper.Get(key[0]) == nil {
-				return key[1]
-			}
-		} else {
-			return nil
-		}
+	if value := viper.Get(key[0]); value != nil {
+		return value
+	}
+	if value, ok := os.LookupEnv(key[0]); ok {
+		return value
 	}
-
 	if len(key) == 2 {
-		if viper.Get(key[0]) == nil {
-			return key[1]
-		}
+		return key[1]
 	}
 
-	return viper.Get(key[0])
+	return nil
 }
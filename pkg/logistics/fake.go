@@ -0,0 +1,43 @@
+package logistics
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 本地模拟物流查询服务
+const TrackerFake = "fake"
+
+// 本地模拟物流查询服务，根据快递单号生成固定的物流轨迹，用于测试和本地开发
+type FakeTracker struct{}
+
+// 初始化本地模拟物流查询服务
+func NewFakeTracker() *FakeTracker {
+	return &FakeTracker{}
+}
+
+// 服务名称
+func (p *FakeTracker) Name() string {
+	return TrackerFake
+}
+
+// 查询物流轨迹
+func (p *FakeTracker) Track(ctx context.Context, req TrackRequest) (*TrackResult, error) {
+	if req.CarrierCode == "" || req.TrackingNo == "" {
+		return nil, errors.New("快递公司和快递单号不能为空")
+	}
+
+	now := time.Now()
+	carrierName := GetCarrierName(req.CarrierCode)
+	return &TrackResult{
+		CarrierCode: req.CarrierCode,
+		TrackingNo:  req.TrackingNo,
+		State:       StateInTransit,
+		Traces: []Trace{
+			{Time: now.Add(-2 * time.Hour), Content: "快件已发往目的地分拨中心"},
+			{Time: now.Add(-6 * time.Hour), Content: "快件已到达始发地分拨中心"},
+			{Time: now.Add(-8 * time.Hour), Content: carrierName + "已揽收"},
+		},
+	}, nil
+}
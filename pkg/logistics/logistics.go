@@ -0,0 +1,117 @@
+package logistics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 物流状态
+const (
+	StateInTransit = "in_transit" // 运输中
+	StateDelivery  = "delivery"   // 派件中
+	StateSigned    = "signed"     // 已签收
+	StateException = "exception"  // 异常
+)
+
+// 常用快递公司，编码使用快递100的公司编码
+var Carriers = []Carrier{
+	{Code: "shunfeng", Name: "顺丰速运"},
+	{Code: "zhongtong", Name: "中通快递"},
+	{Code: "yuantong", Name: "圆通速递"},
+	{Code: "shentong", Name: "申通快递"},
+	{Code: "yunda", Name: "韵达快递"},
+	{Code: "jtexpress", Name: "极兔速递"},
+	{Code: "jd", Name: "京东物流"},
+	{Code: "ems", Name: "EMS"},
+	{Code: "youzhengguonei", Name: "邮政快递包裹"},
+	{Code: "debangkuaidi", Name: "德邦快递"},
+}
+
+// 快递公司
+type Carrier struct {
+	Code string // 快递公司编码
+	Name string // 快递公司名称
+}
+
+// 物流轨迹
+type Trace struct {
+	Time    time.Time // 时间
+	Content string    // 轨迹描述
+}
+
+// 物流查询结果
+type TrackResult struct {
+	CarrierCode string  // 快递公司编码
+	TrackingNo  string  // 快递单号
+	State       string  // 物流状态
+	Traces      []Trace // 物流轨迹，按时间倒序
+}
+
+// 物流查询参数
+type TrackRequest struct {
+	CarrierCode string // 快递公司编码
+	TrackingNo  string // 快递单号
+	Phone       string // 收件人手机号，顺丰等快递公司查询时必填
+}
+
+// 物流轨迹查询服务
+type Tracker interface {
+	Name() string                                                      // 服务名称
+	Track(ctx context.Context, req TrackRequest) (*TrackResult, error) // 查询物流轨迹
+}
+
+// 物流轨迹查询服务构造函数
+type TrackerFactory func() (Tracker, error)
+
+var (
+	trackerMutex     sync.Mutex
+	trackerFactories = map[string]TrackerFactory{}
+	trackers         = map[string]Tracker{}
+)
+
+func init() {
+	RegisterTracker(TrackerFake, func() (Tracker, error) {
+		return NewFakeTracker(), nil
+	})
+}
+
+// 注册物流轨迹查询服务，同名服务会覆盖已注册的服务
+func RegisterTracker(name string, factory TrackerFactory) {
+	trackerMutex.Lock()
+	defer trackerMutex.Unlock()
+
+	trackerFactories[name] = factory
+	delete(trackers, name)
+}
+
+// 获取物流轨迹查询服务，服务在首次使用时初始化
+func GetTracker(name string) (Tracker, error) {
+	trackerMutex.Lock()
+	defer trackerMutex.Unlock()
+
+	if tracker, ok := trackers[name]; ok {
+		return tracker, nil
+	}
+	factory, ok := trackerFactories[name]
+	if !ok {
+		return nil, errors.New("不支持的物流查询服务：" + name)
+	}
+	tracker, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	trackers[name] = tracker
+	return tracker, nil
+}
+
+// 获取快递公司名称
+func GetCarrierName(code string) string {
+	for _, carrier := range Carriers {
+		if carrier.Code == code {
+			return carrier.Name
+		}
+	}
+	return code
+}
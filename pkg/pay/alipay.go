@@ -82,52 +82,52 @@ func NewAliPay(param ...AliPayConfig) *AliPay {
 // 支付宝电脑网站支付
 //
 // 具体传参请参考官方文档：https://opendocs.alipay.com/open/028r8t
-func (p *AliPay) TradePagePay(param map[string]interface{}) (string, error) {
-	var bodyMap gopay.BodyMap
+func (p *AliPay) TradePagePay(ctx context.Context, param map[string]interface{}) (string, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 获取支付链接
-	return p.Client.TradePagePay(context.Background(), bodyMap)
+	return p.Client.TradePagePay(ctx, bodyMap)
 }
 
 // 支付宝手机网站支付
 //
 // 具体传参请参考官方文档：https://opendocs.alipay.com/open/02ivbs
-func (p *AliPay) TradeWapPay(param map[string]interface{}) (string, error) {
-	var bodyMap gopay.BodyMap
+func (p *AliPay) TradeWapPay(ctx context.Context, param map[string]interface{}) (string, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 获取支付链接
-	return p.Client.TradeWapPay(context.Background(), bodyMap)
+	return p.Client.TradeWapPay(ctx, bodyMap)
 }
 
 // 支付宝 APP 支付
 //
 // 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7gq
-func (p *AliPay) TradeAppPay(param map[string]interface{}) (string, error) {
-	var bodyMap gopay.BodyMap
+func (p *AliPay) TradeAppPay(ctx context.Context, param map[string]interface{}) (string, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 获取拉起 APP 支付
-	return p.Client.TradeAppPay(context.Background(), bodyMap)
+	return p.Client.TradeAppPay(ctx, bodyMap)
 }
 
 // 支付宝订单退款
 //
 // 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7go
-func (p *AliPay) TradeRefund(param map[string]interface{}) (*alipay.TradeRefund, error) {
-	var bodyMap gopay.BodyMap
+func (p *AliPay) TradeRefund(ctx context.Context, param map[string]interface{}) (*alipay.TradeRefund, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
-	tradeRefundResponse, err := p.Client.TradeRefund(context.Background(), bodyMap)
+	tradeRefundResponse, err := p.Client.TradeRefund(ctx, bodyMap)
 	if err != nil {
 		return nil, errors.New("支付宝订单退款错误：" + err.Error())
 	}
@@ -137,3 +137,88 @@ func (p *AliPay) TradeRefund(param map[string]interface{}) (*alipay.TradeRefund,
 
 	return tradeRefundResponse.Response, nil
 }
+
+// 支付宝扫码支付，返回二维码链接
+//
+// 具体传参请参考官方文档：https://opendocs.alipay.com/open/02ekfg
+func (p *AliPay) TradePrecreate(ctx context.Context, param map[string]interface{}) (*alipay.TradePrecreate, error) {
+	bodyMap := make(gopay.BodyMap)
+	for key, value := range param {
+		bodyMap.Set(key, value)
+	}
+
+	precreateResponse, err := p.Client.TradePrecreate(ctx, bodyMap)
+	if err != nil {
+		return nil, errors.New("支付宝扫码支付错误：" + err.Error())
+	}
+
+	return precreateResponse.Response, nil
+}
+
+// 支付宝订单查询
+//
+// 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7gm
+func (p *AliPay) TradeQuery(ctx context.Context, param map[string]interface{}) (*alipay.TradeQuery, error) {
+	bodyMap := make(gopay.BodyMap)
+	for key, value := range param {
+		bodyMap.Set(key, value)
+	}
+
+	tradeQueryResponse, err := p.Client.TradeQuery(ctx, bodyMap)
+	if err != nil {
+		return nil, errors.New("支付宝订单查询错误：" + err.Error())
+	}
+
+	return tradeQueryResponse.Response, nil
+}
+
+// 支付宝关闭订单
+//
+// 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7gn
+func (p *AliPay) TradeClose(ctx context.Context, param map[string]interface{}) (*alipay.TradeClose, error) {
+	bodyMap := make(gopay.BodyMap)
+	for key, value := range param {
+		bodyMap.Set(key, value)
+	}
+
+	tradeCloseResponse, err := p.Client.TradeClose(ctx, bodyMap)
+	if err != nil {
+		return nil, errors.New("支付宝关闭订单错误：" + err.Error())
+	}
+
+	return tradeCloseResponse.Response, nil
+}
+
+// 支付宝退款查询
+//
+// 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7gp
+func (p *AliPay) TradeRefundQuery(ctx context.Context, param map[string]interface{}) (*alipay.TradeRefundQuery, error) {
+	bodyMap := make(gopay.BodyMap)
+	for key, value := range param {
+		bodyMap.Set(key, value)
+	}
+
+	refundQueryResponse, err := p.Client.TradeFastPayRefundQuery(ctx, bodyMap)
+	if err != nil {
+		return nil, errors.New("支付宝退款查询错误：" + err.Error())
+	}
+
+	return refundQueryResponse.Response, nil
+}
+
+// 支付宝查询对账单下载地址
+//
+// 具体传参请参考官方文档：https://opendocs.alipay.com/open/02e7gr
+func (p *AliPay) BillDownloadUrlQuery(ctx context.Context, param map[string]interface{}) (string, error) {
+	bodyMap := make(gopay.BodyMap)
+	for key, value := range param {
+		bodyMap.Set(key, value)
+	}
+
+	billResponse, err := p.Client.DataBillDownloadUrlQuery(ctx, bodyMap)
+	if err != nil {
+		return "", errors.New("支付宝查询对账单下载地址错误：" + err.Error())
+	}
+
+	return billResponse.Response.BillDownloadUrl, nil
+}
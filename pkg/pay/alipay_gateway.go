@@ -0,0 +1,176 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 支付宝支付网关
+type AliPayGateway struct {
+	Client *AliPay
+}
+
+// 初始化支付宝支付网关
+func NewAliPayGateway(client *AliPay) *AliPayGateway {
+	return &AliPayGateway{
+		Client: client,
+	}
+}
+
+// 支付渠道
+func (p *AliPayGateway) Channel() string {
+	return ChannelAlipay
+}
+
+// 创建支付
+func (p *AliPayGateway) CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error) {
+	param := map[string]interface{}{
+		"out_trade_no": req.OrderNo,
+		"total_amount": strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		"subject":      req.Subject,
+		"notify_url":   req.NotifyUrl,
+	}
+	if req.ReturnUrl != "" {
+		param["return_url"] = req.ReturnUrl
+	}
+	if !req.ExpireAt.IsZero() {
+		param["time_expire"] = req.ExpireAt.Format(time.DateTime)
+	}
+
+	result := &PaymentResult{Scene: req.Scene}
+	switch req.Scene {
+	case ScenePage:
+		param["product_code"] = "FAST_INSTANT_TRADE_PAY"
+		payUrl, err := p.Client.TradePagePay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Url = payUrl
+	case SceneH5:
+		param["product_code"] = "QUICK_WAP_WAY"
+		payUrl, err := p.Client.TradeWapPay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Url = payUrl
+	case SceneApp:
+		orderStr, err := p.Client.TradeAppPay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Params = orderStr
+	case SceneNative:
+		precreate, err := p.Client.TradePrecreate(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Url = precreate.QrCode
+	default:
+		return nil, errors.New("支付宝不支持该支付场景：" + req.Scene)
+	}
+
+	return result, nil
+}
+
+// 查询支付
+func (p *AliPayGateway) QueryPayment(ctx context.Context, orderNo string) (*PaymentQueryResult, error) {
+	trade, err := p.Client.TradeQuery(ctx, map[string]interface{}{
+		"out_trade_no": orderNo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PaymentQueryResult{
+		OrderNo:       trade.OutTradeNo,
+		TransactionId: trade.TradeNo,
+		Status:        PaymentStatusPending,
+	}
+	switch trade.TradeStatus {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		result.Status = PaymentStatusPaid
+	case "TRADE_CLOSED":
+		result.Status = PaymentStatusClosed
+	}
+	result.Amount, _ = strconv.ParseFloat(trade.TotalAmount, 64)
+	if trade.SendPayDate != "" {
+		result.PaidAt, _ = time.ParseInLocation(time.DateTime, trade.SendPayDate, time.Local)
+	}
+
+	return result, nil
+}
+
+// 申请退款
+func (p *AliPayGateway) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	refund, err := p.Client.TradeRefund(ctx, map[string]interface{}{
+		"out_trade_no":   req.OrderNo,
+		"out_request_no": req.RefundNo,
+		"refund_amount":  strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		"refund_reason":  req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 资金发生变化即表示退款成功
+	status := RefundStatusProcessing
+	if refund.FundChange == "Y" {
+		status = RefundStatusSuccess
+	}
+
+	return &RefundResult{
+		OrderNo:  refund.OutTradeNo,
+		RefundNo: req.RefundNo,
+		RefundId: refund.TradeNo,
+		Amount:   req.Amount,
+		Status:   status,
+	}, nil
+}
+
+// 查询退款
+func (p *AliPayGateway) QueryRefund(ctx context.Context, orderNo, refundNo string) (*RefundResult, error) {
+	refund, err := p.Client.TradeRefundQuery(ctx, map[string]interface{}{
+		"out_trade_no":   orderNo,
+		"out_request_no": refundNo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefundResult{
+		OrderNo:  refund.OutTradeNo,
+		RefundNo: refund.OutRequestNo,
+		RefundId: refund.TradeNo,
+		Status:   RefundStatusProcessing,
+	}
+	if refund.RefundStatus == "REFUND_SUCCESS" {
+		result.Status = RefundStatusSuccess
+	}
+	result.Amount, _ = strconv.ParseFloat(refund.RefundAmount, 64)
+
+	return result, nil
+}
+
+// 关闭支付
+func (p *AliPayGateway) Close(ctx context.Context, orderNo string) error {
+	_, err := p.Client.TradeClose(ctx, map[string]interface{}{
+		"out_trade_no": orderNo,
+	})
+	return err
+}
+
+// 解析异步通知，退款通知按退款单号查询本次退款金额，查询失败时由调用方按累计退款金额计算
+func (p *AliPayGateway) ParseNotification(req *http.Request) (*Notification, error) {
+	notification, err := p.Client.ParseNotify(req)
+	if err != nil || notification.Type != NotifyTypeRefund || notification.RefundNo == "" {
+		return notification, err
+	}
+
+	if refund, err := p.QueryRefund(req.Context(), notification.OrderNo, notification.RefundNo); err == nil {
+		notification.Amount = refund.Amount
+	}
+	return notification, nil
+}
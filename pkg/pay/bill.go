@@ -0,0 +1,234 @@
+package pay
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// 对账单中的一条交易记录
+type BillRecord struct {
+	Type          string  // 记录类型，NotifyTypePayment 或 NotifyTypeRefund
+	OrderNo       string  // 商户订单号
+	TransactionId string  // 支付平台交易号
+	RefundNo      string  // 商户退款单号
+	Amount        float64 // 支付或退款金额，单位元
+}
+
+// 支持下载对账单的支付网关
+type BillDownloader interface {
+	DownloadBill(ctx context.Context, date time.Time) ([]BillRecord, error) // 下载指定日期的交易账单
+}
+
+// 下载微信交易账单
+func (p *WechatGateway) DownloadBill(ctx context.Context, date time.Time) ([]BillRecord, error) {
+	data, err := p.Client.DownloadTradeBill(ctx, date.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	return ParseWechatBill(data)
+}
+
+// 下载支付宝交易账单
+func (p *AliPayGateway) DownloadBill(ctx context.Context, date time.Time) ([]BillRecord, error) {
+	downloadUrl, err := p.Client.BillDownloadUrlQuery(ctx, map[string]interface{}{
+		"bill_type": "trade",
+		"bill_date": date.Format(time.DateOnly),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, errors.New("下载支付宝账单错误：" + err.Error())
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.New("下载支付宝账单错误：" + err.Error())
+	}
+
+	return ParseAliPayBill(data)
+}
+
+// 模拟支付网关的账单，返回内存中已支付、已退款的记录
+func (p *FakeGateway) DownloadBill(ctx context.Context, date time.Time) ([]BillRecord, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	records := []BillRecord{}
+	for _, payment := range p.payments {
+		if payment.Status != PaymentStatusPaid && payment.Status != PaymentStatusRefunded {
+			continue
+		}
+		if payment.PaidAt.Format(time.DateOnly) != date.Format(time.DateOnly) {
+			continue
+		}
+		records = append(records, BillRecord{
+			Type:          NotifyTypePayment,
+			OrderNo:       payment.OrderNo,
+			TransactionId: payment.TransactionId,
+			Amount:        payment.Amount,
+		})
+	}
+	for _, refund := range p.refunds {
+		records = append(records, BillRecord{
+			Type:     NotifyTypeRefund,
+			OrderNo:  refund.OrderNo,
+			RefundNo: refund.RefundNo,
+			Amount:   refund.Amount,
+		})
+	}
+	return records, nil
+}
+
+// 解析微信交易账单
+//
+// 账单为 csv 格式，每个字段以 ` 开头，末尾两行为汇总数据
+func ParseWechatBill(data []byte) ([]BillRecord, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) == 0 {
+		return nil, errors.New("微信账单内容为空")
+	}
+
+	header := map[string]int{}
+	for index, name := range strings.Split(strings.TrimSpace(lines[0]), ",") {
+		header[strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")] = index
+	}
+	amountColumn := "订单金额"
+	if _, ok := header[amountColumn]; !ok {
+		amountColumn = "应结订单金额"
+	}
+
+	records := []BillRecord{}
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "总交易单数") {
+			break
+		}
+		fields := strings.Split(line, ",")
+		value := func(name string) string {
+			index, ok := header[name]
+			if !ok || index >= len(fields) {
+				return ""
+			}
+			return strings.TrimPrefix(strings.TrimSpace(fields[index]), "`")
+		}
+
+		record := BillRecord{
+			Type:          NotifyTypePayment,
+			OrderNo:       value("商户订单号"),
+			TransactionId: value("微信订单号"),
+		}
+		record.Amount, _ = strconv.ParseFloat(value(amountColumn), 64)
+		if value("交易状态") == "REFUND" {
+			record.Type = NotifyTypeRefund
+			record.RefundNo = value("商户退款单号")
+			record.Amount, _ = strconv.ParseFloat(value("退款金额"), 64)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// 解析支付宝交易账单
+//
+// 账单为 zip 压缩包，包含 GBK 编码的业务明细与汇总 csv 文件，此处只解析业务明细
+func ParseAliPayBill(data []byte) ([]BillRecord, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("解压支付宝账单错误：" + err.Error())
+	}
+
+	for _, file := range reader.File {
+		name := file.Name
+		if decoded, err := simplifiedchinese.GBK.NewDecoder().String(name); err == nil && file.Flags&0x800 == 0 {
+			name = decoded
+		}
+		if !strings.Contains(name, "业务明细") || strings.Contains(name, "汇总") {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+
+		return parseAliPayBillDetail(simplifiedchinese.GBK.NewDecoder().Reader(content))
+	}
+
+	return nil, errors.New("支付宝账单中未找到业务明细")
+}
+
+// 解析支付宝业务明细
+func parseAliPayBillDetail(reader io.Reader) ([]BillRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	header := map[string]int{}
+	records := []BillRecord{}
+	for {
+		fields, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("解析支付宝账单错误：" + err.Error())
+		}
+		if len(fields) == 0 || strings.HasPrefix(strings.TrimSpace(fields[0]), "#") {
+			continue
+		}
+
+		// 表头
+		if strings.TrimSpace(fields[0]) == "支付宝交易号" {
+			for index, name := range fields {
+				header[strings.TrimSpace(name)] = index
+			}
+			continue
+		}
+		if len(header) == 0 {
+			continue
+		}
+
+		value := func(name string) string {
+			index, ok := header[name]
+			if !ok || index >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[index])
+		}
+
+		record := BillRecord{
+			Type:          NotifyTypePayment,
+			OrderNo:       value("商户订单号"),
+			TransactionId: value("支付宝交易号"),
+		}
+		amount, _ := strconv.ParseFloat(value("订单金额（元）"), 64)
+		record.Amount = math.Abs(amount)
+		if value("业务类型") == "退款" {
+			record.Type = NotifyTypeRefund
+			record.RefundNo = value("退款批次号/请求号")
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
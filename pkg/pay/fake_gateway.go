@@ -0,0 +1,158 @@
+package pay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 模拟支付渠道
+const ChannelFake = "fake"
+
+// 内存中的模拟支付网关，用于测试和本地开发，不会发起任何网络请求
+type FakeGateway struct {
+	mutex    sync.Mutex
+	payments map[string]*PaymentQueryResult
+	refunds  map[string]*RefundResult
+}
+
+// 初始化模拟支付网关
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		payments: map[string]*PaymentQueryResult{},
+		refunds:  map[string]*RefundResult{},
+	}
+}
+
+// 支付渠道
+func (p *FakeGateway) Channel() string {
+	return ChannelFake
+}
+
+// 创建支付
+func (p *FakeGateway) CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if payment, ok := p.payments[req.OrderNo]; ok && payment.Status != PaymentStatusPending {
+		return nil, errors.New("订单已支付或已关闭")
+	}
+	p.payments[req.OrderNo] = &PaymentQueryResult{
+		OrderNo: req.OrderNo,
+		Status:  PaymentStatusPending,
+		Amount:  req.Amount,
+	}
+
+	return &PaymentResult{
+		Scene: req.Scene,
+		Url:   "fake://pay/" + req.OrderNo,
+	}, nil
+}
+
+// 查询支付
+func (p *FakeGateway) QueryPayment(ctx context.Context, orderNo string) (*PaymentQueryResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	payment, ok := p.payments[orderNo]
+	if !ok {
+		return nil, errors.New("订单不存在")
+	}
+	result := *payment
+	return &result, nil
+}
+
+// 模拟用户完成支付
+func (p *FakeGateway) MarkPaid(orderNo string) (*Notification, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	payment, ok := p.payments[orderNo]
+	if !ok {
+		return nil, errors.New("订单不存在")
+	}
+	if payment.Status != PaymentStatusPending {
+		return nil, errors.New("订单已支付或已关闭")
+	}
+	payment.Status = PaymentStatusPaid
+	payment.TransactionId = "fake" + orderNo
+	payment.PaidAt = time.Now()
+
+	return &Notification{
+		Channel:       ChannelFake,
+		Type:          NotifyTypePayment,
+		OrderNo:       orderNo,
+		TransactionId: payment.TransactionId,
+		Amount:        payment.Amount,
+		Success:       true,
+	}, nil
+}
+
+// 申请退款，立即退款成功
+func (p *FakeGateway) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	payment, ok := p.payments[req.OrderNo]
+	if !ok || (payment.Status != PaymentStatusPaid && payment.Status != PaymentStatusRefunded) {
+		return nil, errors.New("订单未支付")
+	}
+	if refund, ok := p.refunds[req.RefundNo]; ok {
+		result := *refund
+		return &result, nil
+	}
+	payment.Status = PaymentStatusRefunded
+	p.refunds[req.RefundNo] = &RefundResult{
+		OrderNo:  req.OrderNo,
+		RefundNo: req.RefundNo,
+		RefundId: "fake" + req.RefundNo,
+		Amount:   req.Amount,
+		Status:   RefundStatusSuccess,
+	}
+	result := *p.refunds[req.RefundNo]
+	return &result, nil
+}
+
+// 查询退款
+func (p *FakeGateway) QueryRefund(ctx context.Context, orderNo, refundNo string) (*RefundResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	refund, ok := p.refunds[refundNo]
+	if !ok {
+		return nil, errors.New("退款单不存在")
+	}
+	result := *refund
+	return &result, nil
+}
+
+// 关闭支付
+func (p *FakeGateway) Close(ctx context.Context, orderNo string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	payment, ok := p.payments[orderNo]
+	if !ok {
+		return errors.New("订单不存在")
+	}
+	if payment.Status != PaymentStatusPending {
+		return errors.New("订单已支付，不能关闭")
+	}
+	payment.Status = PaymentStatusClosed
+	return nil
+}
+
+// 解析异步通知，请求体为 Notification 的 Json 数据，不做验签
+func (p *FakeGateway) ParseNotification(req *http.Request) (*Notification, error) {
+	defer req.Body.Close()
+
+	notification := &Notification{}
+	if err := json.NewDecoder(req.Body).Decode(notification); err != nil {
+		return nil, errors.New("解析模拟支付通知错误：" + err.Error())
+	}
+	notification.Channel = ChannelFake
+	return notification, nil
+}
@@ -0,0 +1,142 @@
+package pay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFakeGatewayPayment(t *testing.T) {
+	gateway := NewFakeGateway()
+	ctx := context.Background()
+
+	result, err := gateway.CreatePayment(ctx, PaymentRequest{OrderNo: "F001", Amount: 19.99, Scene: SceneApplet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scene != SceneApplet || result.Url == "" {
+		t.Fatalf("unexpected payment result: %+v", result)
+	}
+
+	payment, err := gateway.QueryPayment(ctx, "F001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != PaymentStatusPending || payment.Amount != 19.99 {
+		t.Fatalf("unexpected payment: %+v", payment)
+	}
+
+	notification, err := gateway.MarkPaid("F001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Channel != ChannelFake || notification.Type != NotifyTypePayment || !notification.Success || notification.Amount != 19.99 {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+
+	payment, _ = gateway.QueryPayment(ctx, "F001")
+	if payment.Status != PaymentStatusPaid || payment.TransactionId == "" {
+		t.Fatalf("unexpected payment: %+v", payment)
+	}
+
+	// 已支付的订单不能重复支付、关闭
+	if _, err := gateway.CreatePayment(ctx, PaymentRequest{OrderNo: "F001", Amount: 19.99}); err == nil {
+		t.Fatal("expected error when paying a paid order")
+	}
+	if _, err := gateway.MarkPaid("F001"); err == nil {
+		t.Fatal("expected error when marking a paid order")
+	}
+	if err := gateway.Close(ctx, "F001"); err == nil {
+		t.Fatal("expected error when closing a paid order")
+	}
+}
+
+func TestFakeGatewayClose(t *testing.T) {
+	gateway := NewFakeGateway()
+	ctx := context.Background()
+
+	if err := gateway.Close(ctx, "F002"); err == nil {
+		t.Fatal("expected error when closing an unknown order")
+	}
+
+	gateway.CreatePayment(ctx, PaymentRequest{OrderNo: "F002", Amount: 10})
+	if err := gateway.Close(ctx, "F002"); err != nil {
+		t.Fatal(err)
+	}
+	payment, _ := gateway.QueryPayment(ctx, "F002")
+	if payment.Status != PaymentStatusClosed {
+		t.Fatalf("unexpected payment: %+v", payment)
+	}
+	if _, err := gateway.MarkPaid("F002"); err == nil {
+		t.Fatal("expected error when paying a closed order")
+	}
+}
+
+func TestFakeGatewayRefund(t *testing.T) {
+	gateway := NewFakeGateway()
+	ctx := context.Background()
+
+	gateway.CreatePayment(ctx, PaymentRequest{OrderNo: "F003", Amount: 19.99})
+	if _, err := gateway.Refund(ctx, RefundRequest{OrderNo: "F003", RefundNo: "F003R1", Amount: 5}); err == nil {
+		t.Fatal("expected error when refunding an unpaid order")
+	}
+	gateway.MarkPaid("F003")
+
+	refund, err := gateway.Refund(ctx, RefundRequest{OrderNo: "F003", RefundNo: "F003R1", Amount: 5, TotalAmount: 19.99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != RefundStatusSuccess || refund.Amount != 5 || refund.RefundId == "" {
+		t.Fatalf("unexpected refund: %+v", refund)
+	}
+
+	// 相同退款单号重复提交返回原退款结果
+	again, err := gateway.Refund(ctx, RefundRequest{OrderNo: "F003", RefundNo: "F003R1", Amount: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Amount != 5 || again.RefundId != refund.RefundId {
+		t.Fatalf("refund should be idempotent: %+v", again)
+	}
+
+	// 已退款的订单可以继续部分退款
+	if _, err := gateway.Refund(ctx, RefundRequest{OrderNo: "F003", RefundNo: "F003R2", Amount: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	queried, err := gateway.QueryRefund(ctx, "F003", "F003R2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queried.Amount != 3 || queried.Status != RefundStatusSuccess {
+		t.Fatalf("unexpected refund: %+v", queried)
+	}
+	if _, err := gateway.QueryRefund(ctx, "F003", "F003R3"); err == nil {
+		t.Fatal("expected error when querying an unknown refund")
+	}
+}
+
+func TestFakeGatewayParseNotification(t *testing.T) {
+	gateway := NewFakeGateway()
+	body, _ := json.Marshal(Notification{
+		Channel: ChannelWechat,
+		Type:    NotifyTypeRefund,
+		OrderNo: "F004",
+		Amount:  1.5,
+		Success: true,
+	})
+
+	notification, err := gateway.ParseNotification(httptest.NewRequest("POST", "/api/pay/fake/notify", bytes.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Channel != ChannelFake || notification.Type != NotifyTypeRefund || notification.OrderNo != "F004" || notification.Amount != 1.5 {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+
+	if _, err := gateway.ParseNotification(httptest.NewRequest("POST", "/api/pay/fake/notify", bytes.NewReader([]byte("{")))); err == nil {
+		t.Fatal("expected error for invalid body")
+	}
+}
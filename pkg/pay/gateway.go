@@ -0,0 +1,154 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 支付场景
+const (
+	SceneJSAPI  = "jsapi"  // 公众号支付
+	SceneApplet = "applet" // 小程序支付
+	SceneApp    = "app"    // APP支付
+	SceneH5     = "h5"     // 手机网站支付
+	SceneNative = "native" // 扫码支付
+	ScenePage   = "page"   // 电脑网站支付
+)
+
+// 支付状态
+const (
+	PaymentStatusPending  = "pending"  // 待支付
+	PaymentStatusPaid     = "paid"     // 已支付
+	PaymentStatusClosed   = "closed"   // 已关闭
+	PaymentStatusRefunded = "refunded" // 已转入退款
+)
+
+// 退款状态
+const (
+	RefundStatusProcessing = "processing" // 退款中
+	RefundStatusSuccess    = "success"    // 退款成功
+	RefundStatusFailed     = "failed"     // 退款失败
+)
+
+// 创建支付参数
+type PaymentRequest struct {
+	OrderNo   string    // 商户订单号
+	Amount    float64   // 支付金额，单位元
+	Subject   string    // 订单标题
+	Scene     string    // 支付场景
+	OpenId    string    // 用户标识，公众号、小程序支付必填
+	ClientIp  string    // 用户IP，H5支付必填
+	NotifyUrl string    // 异步通知地址
+	ReturnUrl string    // 支付完成后跳转地址，网站支付使用
+	ExpireAt  time.Time // 支付过期时间
+}
+
+// 创建支付结果
+type PaymentResult struct {
+	Scene  string      // 支付场景
+	Params interface{} // 客户端拉起支付需要的参数
+	Url    string      // 支付链接或二维码链接
+}
+
+// 支付查询结果
+type PaymentQueryResult struct {
+	OrderNo       string    // 商户订单号
+	TransactionId string    // 支付平台交易号
+	Status        string    // 支付状态
+	Amount        float64   // 支付金额，单位元
+	PaidAt        time.Time // 支付时间
+}
+
+// 退款参数
+type RefundRequest struct {
+	OrderNo     string  // 商户订单号
+	RefundNo    string  // 商户退款单号
+	Amount      float64 // 退款金额，单位元
+	TotalAmount float64 // 订单总金额，单位元
+	Reason      string  // 退款原因
+	NotifyUrl   string  // 退款异步通知地址
+}
+
+// 退款结果
+type RefundResult struct {
+	OrderNo  string  // 商户订单号
+	RefundNo string  // 商户退款单号
+	RefundId string  // 支付平台退款单号
+	Amount   float64 // 退款金额，单位元
+	Status   string  // 退款状态
+}
+
+// 支付网关，屏蔽不同支付渠道的差异
+type Gateway interface {
+	Channel() string                                                                  // 支付渠道
+	CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error)    // 创建支付
+	QueryPayment(ctx context.Context, orderNo string) (*PaymentQueryResult, error)    // 查询支付
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)             // 申请退款
+	QueryRefund(ctx context.Context, orderNo, refundNo string) (*RefundResult, error) // 查询退款
+	Close(ctx context.Context, orderNo string) error                                  // 关闭支付
+	ParseNotification(req *http.Request) (*Notification, error)                       // 解析异步通知
+}
+
+// 支付网关构造函数
+type GatewayFactory func() (Gateway, error)
+
+var (
+	gatewayMutex     sync.Mutex
+	gatewayFactories = map[string]GatewayFactory{}
+	gateways         = map[string]Gateway{}
+)
+
+func init() {
+	RegisterGateway(ChannelWechat, func() (Gateway, error) {
+		client := NewWechatPay()
+		if client == nil {
+			return nil, errors.New("微信支付未配置")
+		}
+		return NewWechatGateway(client), nil
+	})
+	RegisterGateway(ChannelAlipay, func() (Gateway, error) {
+		client := NewAliPay()
+		if client == nil {
+			return nil, errors.New("支付宝支付未配置")
+		}
+		return NewAliPayGateway(client), nil
+	})
+}
+
+// 注册支付网关，同名渠道会覆盖已注册的网关
+func RegisterGateway(channel string, factory GatewayFactory) {
+	gatewayMutex.Lock()
+	defer gatewayMutex.Unlock()
+
+	gatewayFactories[channel] = factory
+	delete(gateways, channel)
+}
+
+// 获取支付网关，网关在首次使用时初始化
+func GetGateway(channel string) (Gateway, error) {
+	gatewayMutex.Lock()
+	defer gatewayMutex.Unlock()
+
+	if gateway, ok := gateways[channel]; ok {
+		return gateway, nil
+	}
+	factory, ok := gatewayFactories[channel]
+	if !ok {
+		return nil, errors.New("不支持的支付渠道：" + channel)
+	}
+	gateway, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	gateways[channel] = gateway
+	return gateway, nil
+}
+
+// 金额元转分
+func yuanToFen(amount float64) int {
+	return int(math.Round(amount * 100))
+}
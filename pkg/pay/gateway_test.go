@@ -0,0 +1,66 @@
+package pay
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGatewayRegistry(t *testing.T) {
+	calls := 0
+	RegisterGateway(ChannelFake, func() (Gateway, error) {
+		calls++
+		return NewFakeGateway(), nil
+	})
+
+	first, err := GetGateway(ChannelFake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := GetGateway(ChannelFake)
+	if first != second || calls != 1 {
+		t.Fatalf("gateway should be initialized once, got %d calls", calls)
+	}
+	if first.Channel() != ChannelFake {
+		t.Fatalf("unexpected channel %s", first.Channel())
+	}
+
+	// 重新注册后使用新的网关
+	RegisterGateway(ChannelFake, func() (Gateway, error) {
+		return NewFakeGateway(), nil
+	})
+	third, _ := GetGateway(ChannelFake)
+	if third == first {
+		t.Fatal("gateway should be replaced after register")
+	}
+}
+
+func TestGatewayRegistryErrors(t *testing.T) {
+	if _, err := GetGateway("unknown"); err == nil {
+		t.Fatal("expected error for unknown channel")
+	}
+
+	// 初始化失败不缓存，配置修复后可以重新获取
+	fail := true
+	RegisterGateway("flaky", func() (Gateway, error) {
+		if fail {
+			return nil, errors.New("未配置")
+		}
+		return NewFakeGateway(), nil
+	})
+	if _, err := GetGateway("flaky"); err == nil {
+		t.Fatal("expected factory error")
+	}
+	fail = false
+	if _, err := GetGateway("flaky"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestYuanToFen(t *testing.T) {
+	cases := map[float64]int{0.01: 1, 19.99: 1999, 0.1 + 0.2: 30, 100: 10000}
+	for yuan, fen := range cases {
+		if got := yuanToFen(yuan); got != fen {
+			t.Fatalf("yuanToFen(%v) = %d, want %d", yuan, got, fen)
+		}
+	}
+}
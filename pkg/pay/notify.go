@@ -0,0 +1,134 @@
+package pay
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-pay/gopay/alipay"
+	"github.com/go-pay/gopay/wechat/v3"
+)
+
+// 支付渠道
+const (
+	ChannelWechat = "wechat" // 微信支付
+	ChannelAlipay = "alipay" // 支付宝
+)
+
+// 通知类型
+const (
+	NotifyTypePayment = "payment" // 支付通知
+	NotifyTypeRefund  = "refund"  // 退款通知
+)
+
+// 支付平台异步通知，验签、解密后的结果
+type Notification struct {
+	Channel       string  // 支付渠道
+	Type          string  // 通知类型
+	OrderNo       string  // 商户订单号
+	TransactionId string  // 支付平台交易号
+	RefundNo      string  // 商户退款单号
+	Amount        float64 // 支付或退款金额，单位元，支付宝退款通知不提供本次退款金额时为0
+	RefundTotal   float64 // 交易累计退款金额，单位元，只有支付宝退款通知提供
+	Success       bool    // 支付或退款是否成功
+}
+
+// 解析微信支付通知
+func (p *WechatPay) ParseNotify(req *http.Request) (*Notification, error) {
+	return ParseWechatNotify(req, p.Config.ApiV3Key, p.Client.WxPublicKeyMap())
+}
+
+// 解析微信支付 v3 异步通知，使用微信平台公钥验签，并使用 ApiV3Key 解密通知资源
+//
+// publicKeys 为证书序列号与平台公钥的映射，可通过 Client.WxPublicKeyMap() 获取
+func ParseWechatNotify(req *http.Request, apiV3Key string, publicKeys map[string]*rsa.PublicKey) (*Notification, error) {
+	notifyReq, err := wechat.V3ParseNotify(req)
+	if err != nil {
+		return nil, errors.New("解析微信支付通知错误：" + err.Error())
+	}
+	if err = notifyReq.VerifySignByPKMap(publicKeys); err != nil {
+		return nil, errors.New("微信支付通知验签失败：" + err.Error())
+	}
+
+	// 退款通知
+	if strings.HasPrefix(notifyReq.EventType, "REFUND.") {
+		result, err := notifyReq.DecryptRefundCipherText(apiV3Key)
+		if err != nil {
+			return nil, errors.New("解密微信退款通知错误：" + err.Error())
+		}
+		notification := &Notification{
+			Channel:       ChannelWechat,
+			Type:          NotifyTypeRefund,
+			OrderNo:       result.OutTradeNo,
+			TransactionId: result.TransactionId,
+			RefundNo:      result.OutRefundNo,
+			Success:       result.RefundStatus == "SUCCESS",
+		}
+		if result.Amount != nil {
+			notification.Amount = float64(result.Amount.Refund) / 100
+		}
+		return notification, nil
+	}
+
+	result, err := notifyReq.DecryptPayCipherText(apiV3Key)
+	if err != nil {
+		return nil, errors.New("解密微信支付通知错误：" + err.Error())
+	}
+	notification := &Notification{
+		Channel:       ChannelWechat,
+		Type:          NotifyTypePayment,
+		OrderNo:       result.OutTradeNo,
+		TransactionId: result.TransactionId,
+		Success:       result.TradeState == "SUCCESS",
+	}
+	if result.Amount != nil {
+		notification.Amount = float64(result.Amount.Total) / 100
+	}
+	return notification, nil
+}
+
+// 解析支付宝通知
+func (p *AliPay) ParseNotify(req *http.Request) (*Notification, error) {
+	return ParseAliPayNotify(req, p.Config.AlipayPublicCertPath)
+}
+
+// 解析支付宝异步通知，使用支付宝公钥证书验签
+//
+// alipayPublicCert 为 alipayPublicCert.crt 文件路径或文件内容[]byte
+func ParseAliPayNotify(req *http.Request, alipayPublicCert interface{}) (*Notification, error) {
+	bodyMap, err := alipay.ParseNotifyToBodyMap(req)
+	if err != nil {
+		return nil, errors.New("解析支付宝通知错误：" + err.Error())
+	}
+	ok, err := alipay.VerifySignWithCert(alipayPublicCert, bodyMap)
+	if err != nil || !ok {
+		message := "签名错误"
+		if err != nil {
+			message = err.Error()
+		}
+		return nil, errors.New("支付宝通知验签失败：" + message)
+	}
+
+	notification := &Notification{
+		Channel:       ChannelAlipay,
+		Type:          NotifyTypePayment,
+		OrderNo:       bodyMap.GetString("out_trade_no"),
+		TransactionId: bodyMap.GetString("trade_no"),
+	}
+	tradeStatus := bodyMap.GetString("trade_status")
+
+	// 退款会以交易状态变更的方式通知，refund_fee 为交易的累计退款金额，不是本次退款金额
+	if refundFee := bodyMap.GetString("refund_fee"); refundFee != "" {
+		notification.Type = NotifyTypeRefund
+		notification.RefundNo = bodyMap.GetString("out_biz_no")
+		notification.RefundTotal, _ = strconv.ParseFloat(refundFee, 64)
+		notification.Success = tradeStatus == "TRADE_SUCCESS" || tradeStatus == "TRADE_CLOSED"
+		return notification, nil
+	}
+
+	notification.Amount, _ = strconv.ParseFloat(bodyMap.GetString("total_amount"), 64)
+	notification.Success = tradeStatus == "TRADE_SUCCESS" || tradeStatus == "TRADE_FINISHED"
+	return notification, nil
+}
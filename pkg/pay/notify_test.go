@@ -0,0 +1,189 @@
+package pay
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quarkcloudio/quark-smart/v2/pkg/pay/paytest"
+)
+
+const testApiV3Key = "0123456789abcdef0123456789abcdef"
+
+func newWechatNotifier(t *testing.T) *paytest.WechatNotifier {
+	t.Helper()
+	notifier, err := paytest.NewWechatNotifier(testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notifier
+}
+
+func newAlipayNotifier(t *testing.T) *paytest.AlipayNotifier {
+	t.Helper()
+	notifier, err := paytest.NewAlipayNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notifier
+}
+
+func TestParseWechatNotifyPayment(t *testing.T) {
+	notifier := newWechatNotifier(t)
+	req, err := notifier.Request("TRANSACTION.SUCCESS", map[string]interface{}{
+		"out_trade_no":   "202401010001",
+		"transaction_id": "4200000001",
+		"trade_state":    "SUCCESS",
+		"amount":         map[string]interface{}{"total": 1999, "payer_total": 1999},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification, err := ParseWechatNotify(req, testApiV3Key, notifier.PublicKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Type != NotifyTypePayment || notification.OrderNo != "202401010001" || notification.TransactionId != "4200000001" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+	if !notification.Success || notification.Amount != 19.99 {
+		t.Fatalf("unexpected amount or state: %+v", notification)
+	}
+}
+
+func TestParseWechatNotifyRefund(t *testing.T) {
+	notifier := newWechatNotifier(t)
+	req, err := notifier.Request("REFUND.SUCCESS", map[string]interface{}{
+		"out_trade_no":   "202401010001",
+		"transaction_id": "4200000001",
+		"out_refund_no":  "202401010001R1",
+		"refund_id":      "50000000001",
+		"refund_status":  "SUCCESS",
+		"amount":         map[string]interface{}{"total": 1999, "refund": 500},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification, err := ParseWechatNotify(req, testApiV3Key, notifier.PublicKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Type != NotifyTypeRefund || notification.RefundNo != "202401010001R1" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+	if !notification.Success || notification.Amount != 5 {
+		t.Fatalf("unexpected amount or state: %+v", notification)
+	}
+}
+
+func TestParseWechatNotifyRejectsInvalidSignature(t *testing.T) {
+	notifier := newWechatNotifier(t)
+	other := newWechatNotifier(t)
+	req, err := notifier.Request("TRANSACTION.SUCCESS", map[string]interface{}{
+		"out_trade_no": "202401010001",
+		"trade_state":  "SUCCESS",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 使用其他平台公钥验签
+	publicKeys := map[string]*rsa.PublicKey{notifier.SerialNo: &other.PrivateKey.PublicKey}
+	if _, err := ParseWechatNotify(req, testApiV3Key, publicKeys); err == nil {
+		t.Fatal("expected signature error")
+	}
+}
+
+func TestParseWechatNotifyRejectsWrongApiV3Key(t *testing.T) {
+	notifier := newWechatNotifier(t)
+	req, err := notifier.Request("TRANSACTION.SUCCESS", map[string]interface{}{
+		"out_trade_no": "202401010001",
+		"trade_state":  "SUCCESS",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseWechatNotify(req, strings.Repeat("x", 32), notifier.PublicKeys()); err == nil {
+		t.Fatal("expected decrypt error")
+	}
+}
+
+func TestParseAliPayNotifyPayment(t *testing.T) {
+	notifier := newAlipayNotifier(t)
+	req, err := notifier.Request(map[string]string{
+		"notify_type":  "trade_status_sync",
+		"out_trade_no": "202401010001",
+		"trade_no":     "2024010122001",
+		"trade_status": "TRADE_SUCCESS",
+		"total_amount": "19.99",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification, err := ParseAliPayNotify(req, notifier.PublicCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Type != NotifyTypePayment || notification.OrderNo != "202401010001" || notification.TransactionId != "2024010122001" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+	if !notification.Success || notification.Amount != 19.99 {
+		t.Fatalf("unexpected amount or state: %+v", notification)
+	}
+}
+
+func TestParseAliPayNotifyRefund(t *testing.T) {
+	notifier := newAlipayNotifier(t)
+	req, err := notifier.Request(map[string]string{
+		"notify_type":  "trade_status_sync",
+		"out_trade_no": "202401010001",
+		"trade_no":     "2024010122001",
+		"out_biz_no":   "202401010001R1",
+		"trade_status": "TRADE_SUCCESS",
+		"total_amount": "19.99",
+		"refund_fee":   "5.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notification, err := ParseAliPayNotify(req, notifier.PublicCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notification.Type != NotifyTypeRefund || notification.RefundNo != "202401010001R1" {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+
+	// refund_fee 为累计退款金额，不作为本次退款金额
+	if !notification.Success || notification.Amount != 0 || notification.RefundTotal != 5 {
+		t.Fatalf("unexpected amount or state: %+v", notification)
+	}
+}
+
+func TestParseAliPayNotifyRejectsTamperedParams(t *testing.T) {
+	notifier := newAlipayNotifier(t)
+	form, err := notifier.Sign(map[string]string{
+		"out_trade_no": "202401010001",
+		"trade_status": "TRADE_SUCCESS",
+		"total_amount": "19.99",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 篡改金额后验签失败
+	form.Set("total_amount", "0.01")
+	req := httptest.NewRequest(http.MethodPost, "/api/pay/alipay/notify", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if _, err := ParseAliPayNotify(req, notifier.PublicCert); err == nil {
+		t.Fatal("expected signature error")
+	}
+}
@@ -73,14 +73,14 @@ func NewWechatPay(param ...WechatPayConfig) *WechatPay {
 // 微信 JSAPI 支付
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_1.shtml
-func (p *WechatPay) JSAPIPay(param map[string]interface{}) (*wechat.JSAPIPayParams, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) JSAPIPay(ctx context.Context, param map[string]interface{}) (*wechat.JSAPIPayParams, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 拉起 JSAPI 支付
-	perPayResponse, err := p.Client.V3TransactionJsapi(context.Background(), bodyMap)
+	perPayResponse, err := p.Client.V3TransactionJsapi(ctx, bodyMap)
 	if err != nil {
 		return nil, err
 	}
@@ -95,14 +95,14 @@ func (p *WechatPay) JSAPIPay(param map[string]interface{}) (*wechat.JSAPIPayPara
 // 微信小程序支付
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_5_1.shtml
-func (p *WechatPay) AppletPay(param map[string]interface{}) (*wechat.AppletParams, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) AppletPay(ctx context.Context, param map[string]interface{}) (*wechat.AppletParams, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 拉起 JSAPI 支付
-	perPayResponse, err := p.Client.V3TransactionJsapi(context.Background(), bodyMap)
+	perPayResponse, err := p.Client.V3TransactionJsapi(ctx, bodyMap)
 	if err != nil {
 		return nil, err
 	}
@@ -117,14 +117,14 @@ func (p *WechatPay) AppletPay(param map[string]interface{}) (*wechat.AppletParam
 // 微信 APP 支付
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_2_1.shtml
-func (p *WechatPay) AppPay(param map[string]interface{}) (*wechat.AppPayParams, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) AppPay(ctx context.Context, param map[string]interface{}) (*wechat.AppPayParams, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 拉起 APP 支付
-	perPayResponse, err := p.Client.V3TransactionApp(context.Background(), bodyMap)
+	perPayResponse, err := p.Client.V3TransactionApp(ctx, bodyMap)
 	if err != nil {
 		return nil, err
 	}
@@ -139,14 +139,14 @@ func (p *WechatPay) AppPay(param map[string]interface{}) (*wechat.AppPayParams,
 // 微信 H5 支付
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_3_1.shtml
-func (p *WechatPay) H5Pay(param map[string]interface{}) (*wechat.H5Url, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) H5Pay(ctx context.Context, param map[string]interface{}) (*wechat.H5Url, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 拉起 H5 支付
-	perPayResponse, err := p.Client.V3TransactionH5(context.Background(), bodyMap)
+	perPayResponse, err := p.Client.V3TransactionH5(ctx, bodyMap)
 	if err != nil {
 		return nil, err
 	}
@@ -160,14 +160,14 @@ func (p *WechatPay) H5Pay(param map[string]interface{}) (*wechat.H5Url, error) {
 // 微信 Native 支付
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_4_1.shtml
-func (p *WechatPay) NativePay(param map[string]interface{}) (*wechat.Native, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) NativePay(ctx context.Context, param map[string]interface{}) (*wechat.Native, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
 	// 拉起 Native 支付
-	perPayResponse, err := p.Client.V3TransactionNative(context.Background(), bodyMap)
+	perPayResponse, err := p.Client.V3TransactionNative(ctx, bodyMap)
 	if err != nil {
 		return nil, err
 	}
@@ -181,13 +181,13 @@ func (p *WechatPay) NativePay(param map[string]interface{}) (*wechat.Native, err
 // 微信订单退款
 //
 // 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_9.shtml
-func (p *WechatPay) Refund(param map[string]interface{}) (*wechat.RefundOrderResponse, error) {
-	var bodyMap gopay.BodyMap
+func (p *WechatPay) Refund(ctx context.Context, param map[string]interface{}) (*wechat.RefundOrderResponse, error) {
+	bodyMap := make(gopay.BodyMap)
 	for key, value := range param {
 		bodyMap.Set(key, value)
 	}
 
-	refundResponse, err := p.Client.V3Refund(context.Background(), bodyMap)
+	refundResponse, err := p.Client.V3Refund(ctx, bodyMap)
 	if err != nil {
 		return nil, errors.New("微信订单退款错误：" + err.Error())
 	}
@@ -197,3 +197,72 @@ func (p *WechatPay) Refund(param map[string]interface{}) (*wechat.RefundOrderRes
 
 	return refundResponse.Response, nil
 }
+
+// 微信订单查询，通过商户订单号查询
+//
+// 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_2.shtml
+func (p *WechatPay) QueryOrder(ctx context.Context, orderNo string) (*wechat.QueryOrder, error) {
+	queryResponse, err := p.Client.V3TransactionQueryOrder(ctx, wechat.OutTradeNo, orderNo)
+	if err != nil {
+		return nil, errors.New("微信订单查询错误：" + err.Error())
+	}
+	if queryResponse.Code != wechat.Success {
+		return nil, errors.New("微信订单查询请求错误，错误码：" + strconv.Itoa(queryResponse.Code) + "，错误信息：" + queryResponse.Error)
+	}
+
+	return queryResponse.Response, nil
+}
+
+// 微信关闭订单
+//
+// 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_3.shtml
+func (p *WechatPay) CloseOrder(ctx context.Context, orderNo string) error {
+	closeResponse, err := p.Client.V3TransactionCloseOrder(ctx, orderNo)
+	if err != nil {
+		return errors.New("微信关闭订单错误：" + err.Error())
+	}
+	if closeResponse.Code != wechat.Success {
+		return errors.New("微信关闭订单请求错误，错误码：" + strconv.Itoa(closeResponse.Code) + "，错误信息：" + closeResponse.Error)
+	}
+
+	return nil
+}
+
+// 微信退款查询，通过商户退款单号查询
+//
+// 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_10.shtml
+func (p *WechatPay) QueryRefund(ctx context.Context, refundNo string) (*wechat.RefundQueryResponse, error) {
+	queryResponse, err := p.Client.V3RefundQuery(ctx, refundNo, nil)
+	if err != nil {
+		return nil, errors.New("微信退款查询错误：" + err.Error())
+	}
+	if queryResponse.Code != wechat.Success {
+		return nil, errors.New("微信退款查询请求错误，错误码：" + strconv.Itoa(queryResponse.Code) + "，错误信息：" + queryResponse.Error)
+	}
+
+	return queryResponse.Response, nil
+}
+
+// 微信下载交易账单，billDate 格式为 2006-01-02
+//
+// 具体传参请参考官方文档：https://pay.weixin.qq.com/wiki/doc/apiv3/apis/chapter3_1_6.shtml
+func (p *WechatPay) DownloadTradeBill(ctx context.Context, billDate string) ([]byte, error) {
+	bodyMap := make(gopay.BodyMap)
+	bodyMap.Set("bill_date", billDate)
+	bodyMap.Set("bill_type", "ALL")
+
+	billResponse, err := p.Client.V3BillTradeBill(ctx, bodyMap)
+	if err != nil {
+		return nil, errors.New("微信申请交易账单错误：" + err.Error())
+	}
+	if billResponse.Code != wechat.Success {
+		return nil, errors.New("微信申请交易账单请求错误，错误码：" + strconv.Itoa(billResponse.Code) + "，错误信息：" + billResponse.Error)
+	}
+
+	fileBytes, err := p.Client.V3BillDownLoadBill(ctx, billResponse.Response.DownloadUrl)
+	if err != nil {
+		return nil, errors.New("微信下载交易账单错误：" + err.Error())
+	}
+
+	return fileBytes, nil
+}
@@ -0,0 +1,193 @@
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+// 微信支付网关
+type WechatGateway struct {
+	Client *WechatPay
+}
+
+// 初始化微信支付网关
+func NewWechatGateway(client *WechatPay) *WechatGateway {
+	return &WechatGateway{
+		Client: client,
+	}
+}
+
+// 支付渠道
+func (p *WechatGateway) Channel() string {
+	return ChannelWechat
+}
+
+// 创建支付
+func (p *WechatGateway) CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error) {
+	param := map[string]interface{}{
+		"appid":        utils.GetConfig("WECHAT_APP_ID"),
+		"description":  req.Subject,
+		"out_trade_no": req.OrderNo,
+		"notify_url":   req.NotifyUrl,
+		"amount": map[string]interface{}{
+			"total":    yuanToFen(req.Amount),
+			"currency": "CNY",
+		},
+	}
+	if !req.ExpireAt.IsZero() {
+		param["time_expire"] = req.ExpireAt.Format(time.RFC3339)
+	}
+
+	result := &PaymentResult{Scene: req.Scene}
+	switch req.Scene {
+	case SceneJSAPI, SceneApplet:
+		if req.OpenId == "" {
+			return nil, errors.New("缺少用户openid")
+		}
+		param["payer"] = map[string]interface{}{
+			"openid": req.OpenId,
+		}
+		var err error
+		if req.Scene == SceneJSAPI {
+			result.Params, err = p.Client.JSAPIPay(ctx, param)
+		} else {
+			result.Params, err = p.Client.AppletPay(ctx, param)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case SceneApp:
+		params, err := p.Client.AppPay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Params = params
+	case SceneH5:
+		param["scene_info"] = map[string]interface{}{
+			"payer_client_ip": req.ClientIp,
+			"h5_info": map[string]interface{}{
+				"type": "Wap",
+			},
+		}
+		h5Url, err := p.Client.H5Pay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Url = h5Url.H5Url
+	case SceneNative:
+		native, err := p.Client.NativePay(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		result.Url = native.CodeUrl
+	default:
+		return nil, errors.New("微信支付不支持该支付场景：" + req.Scene)
+	}
+
+	return result, nil
+}
+
+// 查询支付
+func (p *WechatGateway) QueryPayment(ctx context.Context, orderNo string) (*PaymentQueryResult, error) {
+	order, err := p.Client.QueryOrder(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PaymentQueryResult{
+		OrderNo:       order.OutTradeNo,
+		TransactionId: order.TransactionId,
+		Status:        PaymentStatusPending,
+	}
+	switch order.TradeState {
+	case "SUCCESS":
+		result.Status = PaymentStatusPaid
+	case "REFUND":
+		result.Status = PaymentStatusRefunded
+	case "CLOSED", "REVOKED", "PAYERROR":
+		result.Status = PaymentStatusClosed
+	}
+	if order.Amount != nil {
+		result.Amount = float64(order.Amount.Total) / 100
+	}
+	if order.SuccessTime != "" {
+		result.PaidAt, _ = time.Parse(time.RFC3339, order.SuccessTime)
+	}
+
+	return result, nil
+}
+
+// 申请退款
+func (p *WechatGateway) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	param := map[string]interface{}{
+		"out_trade_no":  req.OrderNo,
+		"out_refund_no": req.RefundNo,
+		"reason":        req.Reason,
+		"amount": map[string]interface{}{
+			"refund":   yuanToFen(req.Amount),
+			"total":    yuanToFen(req.TotalAmount),
+			"currency": "CNY",
+		},
+	}
+	if req.NotifyUrl != "" {
+		param["notify_url"] = req.NotifyUrl
+	}
+
+	refund, err := p.Client.Refund(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefundResult{
+		OrderNo:  refund.OutTradeNo,
+		RefundNo: refund.OutRefundNo,
+		RefundId: refund.RefundId,
+		Amount:   req.Amount,
+		Status:   p.refundStatus(refund.Status),
+	}, nil
+}
+
+// 查询退款
+func (p *WechatGateway) QueryRefund(ctx context.Context, orderNo, refundNo string) (*RefundResult, error) {
+	refund, err := p.Client.QueryRefund(ctx, refundNo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefundResult{
+		OrderNo:  refund.OutTradeNo,
+		RefundNo: refund.OutRefundNo,
+		RefundId: refund.RefundId,
+		Status:   p.refundStatus(refund.Status),
+	}
+	if refund.Amount != nil {
+		result.Amount = float64(refund.Amount.Refund) / 100
+	}
+
+	return result, nil
+}
+
+// 关闭支付
+func (p *WechatGateway) Close(ctx context.Context, orderNo string) error {
+	return p.Client.CloseOrder(ctx, orderNo)
+}
+
+// 解析异步通知
+func (p *WechatGateway) ParseNotification(req *http.Request) (*Notification, error) {
+	return p.Client.ParseNotify(req)
+}
+
+// 微信退款状态转换
+func (p *WechatGateway) refundStatus(status string) string {
+	switch status {
+	case "SUCCESS":
+		return RefundStatusSuccess
+	case "CLOSED", "ABNORMAL":
+		return RefundStatusFailed
+	}
+	return RefundStatusProcessing
+}
@@ -0,0 +1,128 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 数据库驱动取出任务后的占用时间，超时未确认的任务会被重新取出，需大于任务的超时时间
+const DefaultReserveTimeout = 10 * time.Minute
+
+// 数据库驱动的任务表
+type Job struct {
+	Id          int        `json:"id" gorm:"autoIncrement"`
+	TaskId      string     `json:"task_id" gorm:"size:32;not null;uniqueIndex"`
+	Type        string     `json:"type" gorm:"size:100;not null"`
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Attempts    int        `json:"attempts" gorm:"size:11;not null;default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"size:11;not null;default:0"`
+	AvailableAt time.Time  `json:"available_at" gorm:"index"`
+	ReservedAt  *time.Time `json:"reserved_at"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// 表名
+func (Job) TableName() string {
+	return "queue_jobs"
+}
+
+// 死信表，保存超过最大执行次数的任务，所有驱动共用
+type FailedJob struct {
+	Id        int       `json:"id" gorm:"autoIncrement"`
+	TaskId    string    `json:"task_id" gorm:"size:32;not null;index"`
+	Type      string    `json:"type" gorm:"size:100;not null;index"`
+	Payload   string    `json:"payload" gorm:"type:text"`
+	Attempts  int       `json:"attempts" gorm:"size:11;not null;default:0"`
+	Error     string    `json:"error" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// 表名
+func (FailedJob) TableName() string {
+	return "queue_failed_jobs"
+}
+
+// 数据库队列驱动
+type DatabaseDriver struct {
+	DB             *gorm.DB
+	ReserveTimeout time.Duration
+}
+
+// 初始化数据库队列驱动
+func NewDatabaseDriver(db *gorm.DB) *DatabaseDriver {
+	return &DatabaseDriver{
+		DB:             db,
+		ReserveTimeout: DefaultReserveTimeout,
+	}
+}
+
+// 入队
+func (p *DatabaseDriver) Push(ctx context.Context, task *Task) error {
+	return p.DB.WithContext(ctx).Create(&Job{
+		TaskId:      task.Id,
+		Type:        task.Type,
+		Payload:     string(task.Payload),
+		Attempts:    task.Attempts,
+		MaxAttempts: task.MaxAttempts,
+		AvailableAt: task.AvailableAt,
+		LastError:   task.LastError,
+		CreatedAt:   task.CreatedAt,
+	}).Error
+}
+
+// 取出一个到期的任务，跳过其他工作协程已锁定的行
+func (p *DatabaseDriver) Pop(ctx context.Context) (task *Task, err error) {
+	err = p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		job := Job{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("available_at <= ?", now).
+			Where("reserved_at IS NULL OR reserved_at < ?", now.Add(-p.ReserveTimeout)).
+			Order("id asc").
+			First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&job).Update("reserved_at", now).Error; err != nil {
+			return err
+		}
+
+		task = &Task{
+			Id:          job.TaskId,
+			Type:        job.Type,
+			Payload:     []byte(job.Payload),
+			Attempts:    job.Attempts,
+			MaxAttempts: job.MaxAttempts,
+			AvailableAt: job.AvailableAt,
+			LastError:   job.LastError,
+			CreatedAt:   job.CreatedAt,
+		}
+		return nil
+	})
+	return task, err
+}
+
+// 删除任务
+func (p *DatabaseDriver) Ack(ctx context.Context, task *Task) error {
+	return p.DB.WithContext(ctx).Where("task_id = ?", task.Id).Delete(&Job{}).Error
+}
+
+// 释放任务
+func (p *DatabaseDriver) Release(ctx context.Context, task *Task) error {
+	return p.DB.WithContext(ctx).Model(&Job{}).Where("task_id = ?", task.Id).Updates(map[string]interface{}{
+		"attempts":     task.Attempts,
+		"available_at": task.AvailableAt,
+		"reserved_at":  nil,
+		"last_error":   task.LastError,
+	}).Error
+}
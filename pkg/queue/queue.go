@@ -0,0 +1,316 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/db"
+	"github.com/quarkcloudio/quark-go/v3/dal/redis"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
+)
+
+// 默认最大执行次数，包含第一次执行
+const DefaultMaxAttempts = 5
+
+// 默认任务超时时间
+const DefaultTimeout = time.Minute
+
+// 重试退避的基础间隔与上限，第 n 次重试等待 BaseBackoff*2^(n-1)
+const (
+	BaseBackoff = 10 * time.Second
+	MaxBackoff  = time.Hour
+)
+
+// 队列为空时的轮询间隔
+const pollInterval = time.Second
+
+// 任务
+type Task struct {
+	Id          string          `json:"id"`           // 任务ID
+	Type        string          `json:"type"`         // 任务类型
+	Payload     json.RawMessage `json:"payload"`      // 任务参数
+	Attempts    int             `json:"attempts"`     // 已执行次数
+	MaxAttempts int             `json:"max_attempts"` // 最大执行次数
+	AvailableAt time.Time       `json:"available_at"` // 可执行时间
+	LastError   string          `json:"last_error"`   // 最近一次错误信息
+	CreatedAt   time.Time       `json:"created_at"`   // 创建时间
+	member      string          // Redis 驱动中任务的原始数据
+}
+
+// 队列驱动
+type Driver interface {
+	Push(ctx context.Context, task *Task) error    // 入队，AvailableAt 之后才能被取出
+	Pop(ctx context.Context) (*Task, error)        // 取出一个到期的任务并占用，无任务时返回 nil
+	Ack(ctx context.Context, task *Task) error     // 删除已取出的任务
+	Release(ctx context.Context, task *Task) error // 释放已取出的任务，按 AvailableAt 重新入队
+}
+
+// 任务处理函数
+type Handler func(ctx context.Context, task *Task) error
+
+// 任务处理配置
+type handlerConfig struct {
+	handler     Handler
+	maxAttempts int
+	timeout     time.Duration
+}
+
+// 注册任务处理函数的选项
+type HandlerOption func(*handlerConfig)
+
+// 设置最大执行次数
+func WithMaxAttempts(maxAttempts int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.maxAttempts = maxAttempts
+	}
+}
+
+// 设置任务超时时间
+func WithTimeout(timeout time.Duration) HandlerOption {
+	return func(c *handlerConfig) {
+		c.timeout = timeout
+	}
+}
+
+// 入队选项
+type DispatchOption func(*Task)
+
+// 延迟执行
+func WithDelay(delay time.Duration) DispatchOption {
+	return func(t *Task) {
+		t.AvailableAt = time.Now().Add(delay)
+	}
+}
+
+type Queue struct {
+	Driver   Driver
+	mutex    sync.RWMutex
+	handlers map[string]handlerConfig
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+var once sync.Once
+var queue *Queue
+
+// 初始化队列，配置了 Redis 时使用 Redis 驱动，否则使用数据库驱动
+func NewQueue() *Queue {
+	// 单例模式初始化队列
+	once.Do(func() {
+		var driver Driver
+		if redis.Client != nil {
+			driver = NewRedisDriver(redis.Client)
+		} else {
+			driver = NewDatabaseDriver(db.Client)
+		}
+		queue = &Queue{
+			Driver:   driver,
+			handlers: map[string]handlerConfig{},
+		}
+	})
+
+	return queue
+}
+
+// 注册任务处理函数，同类型的任务会覆盖已注册的处理函数
+func (p *Queue) Register(taskType string, handler Handler, options ...HandlerOption) {
+	config := handlerConfig{
+		handler:     handler,
+		maxAttempts: DefaultMaxAttempts,
+		timeout:     DefaultTimeout,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.handlers[taskType] = config
+}
+
+// 注册带类型参数的任务处理函数，任务参数自动解析为 T
+func RegisterFunc[T any](p *Queue, taskType string, handler func(ctx context.Context, payload T) error, options ...HandlerOption) {
+	p.Register(taskType, func(ctx context.Context, task *Task) error {
+		var payload T
+		if err := json.Unmarshal(task.Payload, &payload); err != nil {
+			return err
+		}
+		return handler(ctx, payload)
+	}, options...)
+}
+
+// 投递任务，任务由后台工作协程异步执行
+func (p *Queue) Dispatch(taskType string, payload interface{}, options ...DispatchOption) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	task := &Task{
+		Id:          rand.MakeAlphanumeric(32),
+		Type:        taskType,
+		Payload:     data,
+		AvailableAt: now,
+		CreatedAt:   now,
+	}
+	if config, ok := p.getHandler(taskType); ok {
+		task.MaxAttempts = config.maxAttempts
+	}
+	for _, option := range options {
+		option(task)
+	}
+
+	return p.Driver.Push(context.Background(), task)
+}
+
+// 投递任务
+func Dispatch(taskType string, payload interface{}, options ...DispatchOption) error {
+	return NewQueue().Dispatch(taskType, payload, options...)
+}
+
+// 启动指定数量的工作协程
+func (p *Queue) Start(workers int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(ctx)
+		}()
+	}
+}
+
+// 停止工作协程，等待执行中的任务完成
+func (p *Queue) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// 工作协程，循环取出任务并执行
+func (p *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		task, err := p.Driver.Pop(ctx)
+		if err != nil {
+			log.Println("队列取出任务错误：" + err.Error())
+		}
+		if task == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		p.process(task)
+	}
+}
+
+// 执行任务，失败时按指数退避重试，超过最大执行次数移入死信
+func (p *Queue) process(task *Task) {
+	config, ok := p.getHandler(task.Type)
+	if !ok {
+		task.Attempts++
+		p.bury(task, errors.New("未注册的任务类型："+task.Type))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout)
+	defer cancel()
+
+	task.Attempts++
+	err := p.call(ctx, config.handler, task)
+	if err == nil {
+		if err := p.Driver.Ack(context.Background(), task); err != nil {
+			log.Println("队列删除任务错误：" + err.Error())
+		}
+		return
+	}
+
+	maxAttempts := task.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = config.maxAttempts
+	}
+	if task.Attempts >= maxAttempts {
+		p.bury(task, err)
+		return
+	}
+
+	task.LastError = err.Error()
+	task.AvailableAt = time.Now().Add(Backoff(task.Attempts))
+	if err := p.Driver.Release(context.Background(), task); err != nil {
+		log.Println("队列重试任务错误：" + err.Error())
+	}
+}
+
+// 调用处理函数，处理函数 panic 时视为执行失败
+func (p *Queue) call(ctx context.Context, handler Handler, task *Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("任务执行异常")
+			log.Println("队列任务"+task.Type+"执行异常：", r)
+		}
+	}()
+	return handler(ctx, task)
+}
+
+// 移入死信
+func (p *Queue) bury(task *Task, err error) {
+	task.LastError = err.Error()
+	log.Println("队列任务" + task.Type + "执行失败：" + task.LastError)
+
+	if err := saveFailedTask(task); err != nil {
+		log.Println("队列保存死信错误：" + err.Error())
+		return
+	}
+	if err := p.Driver.Ack(context.Background(), task); err != nil {
+		log.Println("队列删除任务错误：" + err.Error())
+	}
+}
+
+// 获取任务处理配置
+func (p *Queue) getHandler(taskType string) (handlerConfig, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	config, ok := p.handlers[taskType]
+	return config, ok
+}
+
+// 第 attempts 次执行失败后的重试等待时间
+func Backoff(attempts int) time.Duration {
+	backoff := time.Duration(float64(BaseBackoff) * math.Pow(2, float64(attempts-1)))
+	if backoff <= 0 || backoff > MaxBackoff {
+		return MaxBackoff
+	}
+	return backoff
+}
+
+// 保存死信
+func saveFailedTask(task *Task) error {
+	return db.Client.Create(&FailedJob{
+		TaskId:    task.Id,
+		Type:      task.Type,
+		Payload:   string(task.Payload),
+		Attempts:  task.Attempts,
+		Error:     task.LastError,
+		CreatedAt: task.CreatedAt,
+		FailedAt:  time.Now(),
+	}).Error
+}
@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 驱动的键名
+const (
+	redisPendingKey  = "queue:pending"  // 待执行任务，有序集合，分数为可执行时间
+	redisReservedKey = "queue:reserved" // 已取出任务，有序集合，分数为占用到期时间
+)
+
+// 取出任务，先将占用超时的任务放回待执行队列，再取出一个到期的任务
+var popScript = redis.NewScript(`
+local expired = redis.call("zrangebyscore", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, 100)
+for _, member in ipairs(expired) do
+	redis.call("zrem", KEYS[2], member)
+	redis.call("zadd", KEYS[1], ARGV[1], member)
+end
+local members = redis.call("zrangebyscore", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, 1)
+if #members == 0 then
+	return false
+end
+redis.call("zrem", KEYS[1], members[1])
+redis.call("zadd", KEYS[2], ARGV[2], members[1])
+return members[1]
+`)
+
+// Redis 队列驱动
+type RedisDriver struct {
+	Client         *redis.Client
+	ReserveTimeout time.Duration
+}
+
+// 初始化 Redis 队列驱动
+func NewRedisDriver(client *redis.Client) *RedisDriver {
+	return &RedisDriver{
+		Client:         client,
+		ReserveTimeout: DefaultReserveTimeout,
+	}
+}
+
+// 入队
+func (p *RedisDriver) Push(ctx context.Context, task *Task) error {
+	member, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	return p.Client.ZAdd(ctx, redisPendingKey, redis.Z{
+		Score:  float64(task.AvailableAt.UnixMilli()),
+		Member: string(member),
+	}).Err()
+}
+
+// 取出一个到期的任务
+func (p *RedisDriver) Pop(ctx context.Context) (*Task, error) {
+	now := time.Now()
+	member, err := popScript.Run(ctx, p.Client, []string{redisPendingKey, redisReservedKey},
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(now.Add(p.ReserveTimeout).UnixMilli(), 10),
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	task := &Task{}
+	if err := json.Unmarshal([]byte(member), task); err != nil {
+		// 无法解析的任务直接丢弃，避免阻塞队列
+		p.Client.ZRem(ctx, redisReservedKey, member)
+		return nil, err
+	}
+	task.member = member
+	return task, nil
+}
+
+// 删除任务
+func (p *RedisDriver) Ack(ctx context.Context, task *Task) error {
+	return p.Client.ZRem(ctx, redisReservedKey, task.member).Err()
+}
+
+// 释放任务
+func (p *RedisDriver) Release(ctx context.Context, task *Task) error {
+	member, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	_, err = p.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, redisReservedKey, task.member)
+		pipe.ZAdd(ctx, redisPendingKey, redis.Z{
+			Score:  float64(task.AvailableAt.UnixMilli()),
+			Member: string(member),
+		})
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/quarkcloudio/quark-go/v3/dal/redis"
+	"github.com/quarkcloudio/quark-go/v3/utils/rand"
+)
+
+// 分布式锁键名前缀
+const lockKeyPrefix = "scheduler:lock:"
+
+// 只删除自己持有的锁，避免任务超时后误删其他实例的锁
+const unlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// 只续期自己持有的锁
+const renewScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+// 获取任务的分布式锁，使多实例部署时同一时刻只有一个实例执行
+//
+// 配置了 Redis 时通过 Redis 分布式锁互斥，未获取到锁返回 false；
+// 未配置 Redis 时视为单实例部署，直接返回 true。持有锁期间每隔 ttl/3 续期一次，
+// 任务执行时间超过 ttl 也不会被其他实例重复执行
+func lock(name string, ttl time.Duration) (unlock func(), locked bool) {
+	if redis.Client == nil {
+		return func() {}, true
+	}
+
+	ctx := context.Background()
+	key := lockKeyPrefix + name
+	token := rand.MakeAlphanumeric(16)
+	locked, err := redis.Client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !locked {
+		return nil, false
+	}
+
+	done := make(chan struct{})
+	go renew(key, token, ttl, done)
+
+	return func() {
+		close(done)
+		redis.Client.Eval(ctx, unlockScript, []string{key}, token)
+	}, true
+}
+
+// 定时续期，直到释放锁或锁已被其他实例持有
+func renew(key string, token string, ttl time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			renewed, err := redis.Client.Eval(context.Background(), renewScript, []string{key}, token, ttl.Milliseconds()).Int()
+			if err == nil && renewed == 0 {
+				return
+			}
+		}
+	}
+}
@@ -1,14 +1,49 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron"
 )
 
+// 任务触发方式
+const (
+	TriggerCron   = "cron"   // 定时触发
+	TriggerManual = "manual" // 手动触发
+)
+
+// 分布式锁的过期时间，任务执行期间自动续期，实例异常退出时锁在过期后释放
+const LockTTL = 30 * time.Second
+
+// 定时任务
+type Job struct {
+	Name      string                          // 任务名称，全局唯一
+	Title     string                          // 任务说明
+	Spec      string                          // cron 表达式，如 */5 * * * *
+	Timeout   time.Duration                   // 超时时间，到期后取消任务的 context，分布式锁的过期时间固定为 LockTTL
+	Singleton bool                            // 单例模式，上一次未执行完时跳过本次执行
+	Handle    func(ctx context.Context) error // 任务处理函数
+}
+
+// 任务执行记录
+type Run struct {
+	Name      string        // 任务名称
+	Trigger   string        // 触发方式
+	StartedAt time.Time     // 开始时间
+	Duration  time.Duration // 执行耗时
+	Err       error         // 执行错误
+}
+
 type Scheduler struct {
-	Cron *gocron.Scheduler
+	Cron      *gocron.Scheduler
+	BeforeRun func(name string) bool // 定时触发前调用，返回 false 时跳过本次执行，用于启用、禁用任务
+	AfterRun  func(run Run)          // 任务执行后调用，用于保存执行记录
+	mutex     sync.Mutex
+	jobs      []Job
+	running   map[string]bool
 }
 
 var once sync.Once
@@ -21,14 +56,153 @@ func NewScheduler() *Scheduler {
 		// 设置时区
 		location, _ := time.LoadLocation("Asia/Shanghai")
 		scheduler = &Scheduler{
-			Cron: gocron.NewScheduler(location),
+			Cron:    gocron.NewScheduler(location),
+			running: map[string]bool{},
 		}
 	})
 
 	return scheduler
 }
 
+// 注册定时任务
+func (p *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Spec == "" || job.Handle == nil {
+		return errors.New("任务名称、cron 表达式和处理函数不能为空")
+	}
+	if _, ok := p.GetJob(job.Name); ok {
+		return errors.New("任务已注册：" + job.Name)
+	}
+
+	_, err := p.Cron.Cron(job.Spec).Tag(job.Name).Do(func() {
+		p.run(job, TriggerCron)
+	})
+	if err != nil {
+		return errors.New("注册任务" + job.Name + "错误：" + err.Error())
+	}
+
+	p.mutex.Lock()
+	p.jobs = append(p.jobs, job)
+	p.mutex.Unlock()
+	return nil
+}
+
+// 获取已注册的任务，按注册顺序返回
+func (p *Scheduler) Jobs() []Job {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]Job{}, p.jobs...)
+}
+
+// 通过名称获取已注册的任务
+func (p *Scheduler) GetJob(name string) (Job, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, job := range p.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// 立即执行任务，任务在后台执行，不受启用状态影响，因任务正在执行而跳过时通过 AfterRun 记录
+func (p *Scheduler) RunNow(name string) error {
+	job, ok := p.GetJob(name)
+	if !ok {
+		return errors.New("任务不存在：" + name)
+	}
+	if job.Singleton && p.isRunning(name) {
+		return errors.New("任务正在执行中")
+	}
+
+	go p.run(job, TriggerManual)
+	return nil
+}
+
 // 启动调度器
 func (p *Scheduler) Start() {
 	p.Cron.StartAsync()
 }
+
+// 执行任务
+func (p *Scheduler) run(job Job, trigger string) {
+	if trigger == TriggerCron && p.BeforeRun != nil && !p.BeforeRun(job.Name) {
+		return
+	}
+
+	// 单例任务在本实例内互斥
+	if job.Singleton {
+		if !p.setRunning(job.Name, true) {
+			p.skip(job, trigger, "任务正在执行中")
+			return
+		}
+		defer p.setRunning(job.Name, false)
+	}
+
+	// 多实例部署时通过分布式锁互斥
+	unlock, locked := lock(job.Name, LockTTL)
+	if !locked {
+		p.skip(job, trigger, "任务正在其他实例执行")
+		return
+	}
+	defer unlock()
+
+	ctx := context.Background()
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	startedAt := time.Now()
+	err := job.Handle(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = errors.New("任务执行超时")
+	}
+
+	if p.AfterRun != nil {
+		p.AfterRun(Run{
+			Name:      job.Name,
+			Trigger:   trigger,
+			StartedAt: startedAt,
+			Duration:  time.Since(startedAt),
+			Err:       err,
+		})
+	}
+}
+
+// 记录跳过的手动触发，定时触发在多实例部署时每次都有实例跳过，不记录
+func (p *Scheduler) skip(job Job, trigger string, reason string) {
+	if trigger != TriggerManual || p.AfterRun == nil {
+		return
+	}
+
+	p.AfterRun(Run{
+		Name:      job.Name,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+		Err:       errors.New(reason),
+	})
+}
+
+// 任务是否正在执行
+func (p *Scheduler) isRunning(name string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.running[name]
+}
+
+// 设置任务执行状态，任务已在执行时标记失败
+func (p *Scheduler) setRunning(name string, running bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if running && p.running[name] {
+		return false
+	}
+	p.running[name] = running
+	return true
+}
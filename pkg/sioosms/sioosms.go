@@ -3,8 +3,11 @@ package sioosms
 import (
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
+	"strconv"
 
 	"github.com/parnurzeal/gorequest"
 )
@@ -41,34 +44,63 @@ func (p *App) SendSms(phone string, content string) (bool, string) {
 		return false, "手机号格式错误！"
 	}
 
+	if _, err := p.Send(phone, content); err != nil {
+		return false, err.Error()
+	}
+
+	return true, "发送成功"
+}
+
+// 接口返回结果
+type Result struct {
+	Code  int
+	Msg   string
+	MsgId string
+}
+
+// 短信平台返回的错误
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return strconv.Itoa(e.Code) + "：" + e.Message
+}
+
+// 发送短信，接口返回的 Code 不为 0 时返回错误
+func (p *App) Send(phone string, content string) (*Result, error) {
 	uid := p.Config.Uid
 	password := p.Config.Password
 
 	if uid == "" || password == "" {
-		return false, "接口配置错误！"
+		return nil, errors.New("接口配置错误！")
 	}
 
 	md5Byte := md5.Sum([]byte(password))
 	md5Password := fmt.Sprintf("%x", md5Byte)
 
-	// 接口url
-	url := "https://submit.10690221.com/send/ordinarykv?uid=" + uid + "&password=" + md5Password + "&mobile=" + phone + "&msg=" + content
+	// 接口url，短信内容需要转义
+	query := url.Values{}
+	query.Set("uid", uid)
+	query.Set("password", md5Password)
+	query.Set("mobile", phone)
+	query.Set("msg", content)
+	requestUrl := "https://submit.10690221.com/send/ordinarykv?" + query.Encode()
 
 	request := gorequest.New()
-	_, body, _ := request.Get(url).End()
-
-	type Data struct {
-		Msg   string
-		Code  int
-		MsgId string
+	_, body, errs := request.Get(requestUrl).End()
+	if len(errs) > 0 {
+		return nil, errs[0]
 	}
 
-	var data Data
-	json.Unmarshal([]byte(body), &data)
-
-	if data.Code == 0 {
-		return true, "发送成功"
-	} else {
-		return true, data.Msg
+	result := &Result{}
+	if err := json.Unmarshal([]byte(body), result); err != nil {
+		return nil, errors.New("解析接口返回错误：" + body)
+	}
+	if result.Code != 0 {
+		return result, &Error{Code: result.Code, Message: result.Msg}
 	}
+
+	return result, nil
 }
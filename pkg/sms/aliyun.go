@@ -0,0 +1,62 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/quarkcloudio/quark-smart/v2/pkg/aliyunsms"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+// 阿里云短信发送器
+type AliyunSender struct {
+	App *aliyunsms.App
+}
+
+// 初始化阿里云短信发送器
+func NewAliyunSender() *AliyunSender {
+	return &AliyunSender{
+		App: aliyunsms.New(&aliyunsms.Config{
+			AccessKeyId:     utils.GetConfig("ALIYUN_SMS_ACCESS_KEY_ID"),
+			AccessKeySecret: utils.GetConfig("ALIYUN_SMS_ACCESS_KEY_SECRET"),
+			SignName:        utils.GetConfig("ALIYUN_SMS_SIGN_NAME"),
+			TemplateCode:    utils.GetConfig("ALIYUN_SMS_TEMPLATE_CODE"),
+		}),
+	}
+}
+
+// 服务商名称
+func (p *AliyunSender) Name() string {
+	return ProviderAliyun
+}
+
+// 发送模板短信
+func (p *AliyunSender) Send(ctx context.Context, msg Message) error {
+	if err := CheckPhone(msg.Phone); err != nil {
+		return err
+	}
+
+	templateCode := msg.TemplateCode
+	if templateCode == "" {
+		templateCode = p.App.Config.TemplateCode
+	}
+	if p.App.Config.AccessKeyId == "" || templateCode == "" {
+		return &Error{Provider: ProviderAliyun, Message: "接口未配置"}
+	}
+
+	params, err := json.Marshal(msg.Params)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.App.Send(msg.Phone, templateCode, string(params))
+	if err != nil {
+		var apiError *aliyunsms.Error
+		if errors.As(err, &apiError) {
+			return &Error{Provider: ProviderAliyun, Code: apiError.Code, Message: apiError.Message}
+		}
+		return &Error{Provider: ProviderAliyun, Message: err.Error()}
+	}
+	return nil
+}
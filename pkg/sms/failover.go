@@ -0,0 +1,48 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// 故障转移发送器，按顺序尝试，前一个服务商发送失败时使用下一个
+type FailoverSender struct {
+	Senders []Sender
+}
+
+// 初始化故障转移发送器
+func NewFailoverSender(senders ...Sender) *FailoverSender {
+	return &FailoverSender{
+		Senders: senders,
+	}
+}
+
+// 服务商名称
+func (p *FailoverSender) Name() string {
+	names := []string{}
+	for _, sender := range p.Senders {
+		names = append(names, sender.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// 发送短信，全部服务商都失败时返回所有错误
+func (p *FailoverSender) Send(ctx context.Context, msg Message) error {
+	errs := []error{}
+	for _, sender := range p.Senders {
+		err := sender.Send(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrInvalidPhone) {
+			return err
+		}
+		errs = append(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package sms
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// 模拟短信服务商
+const ProviderMock = "mock"
+
+// 内存中的模拟短信发送器，用于测试和本地开发，短信内容只打印到日志
+type MockSender struct {
+	mutex    sync.Mutex
+	messages []Message
+	err      error
+}
+
+// 初始化模拟短信发送器
+func NewMockSender() *MockSender {
+	return &MockSender{}
+}
+
+// 服务商名称
+func (p *MockSender) Name() string {
+	return ProviderMock
+}
+
+// 发送短信
+func (p *MockSender) Send(ctx context.Context, msg Message) error {
+	if err := CheckPhone(msg.Phone); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, msg)
+	log.Println("模拟短信发送，手机号：", msg.Phone, "，参数：", msg.Params, "，内容：", msg.Content)
+	return nil
+}
+
+// 设置发送错误，用于模拟服务商故障，传入 nil 恢复正常
+func (p *MockSender) SetError(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.err = err
+}
+
+// 获取已发送的短信
+func (p *MockSender) Messages() []Message {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]Message{}, p.messages...)
+}
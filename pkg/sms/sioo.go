@@ -0,0 +1,50 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/quarkcloudio/quark-smart/v2/pkg/sioosms"
+	"github.com/quarkcloudio/quark-smart/v2/pkg/utils"
+)
+
+// 希奥短信发送器
+type SiooSender struct {
+	App *sioosms.App
+}
+
+// 初始化希奥短信发送器
+func NewSiooSender() *SiooSender {
+	return &SiooSender{
+		App: sioosms.New(&sioosms.Config{
+			Uid:      utils.GetConfig("SIOO_SMS_UID"),
+			Password: utils.GetConfig("SIOO_SMS_PASSWORD"),
+		}),
+	}
+}
+
+// 服务商名称
+func (p *SiooSender) Name() string {
+	return ProviderSioo
+}
+
+// 发送内容短信
+func (p *SiooSender) Send(ctx context.Context, msg Message) error {
+	if err := CheckPhone(msg.Phone); err != nil {
+		return err
+	}
+	if msg.Content == "" {
+		return &Error{Provider: ProviderSioo, Message: "短信内容不能为空"}
+	}
+
+	_, err := p.App.Send(msg.Phone, msg.Content)
+	if err != nil {
+		var apiError *sioosms.Error
+		if errors.As(err, &apiError) {
+			return &Error{Provider: ProviderSioo, Code: strconv.Itoa(apiError.Code), Message: apiError.Message}
+		}
+		return &Error{Provider: ProviderSioo, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 短信服务商
+const (
+	ProviderAliyun = "aliyun" // 阿里云短信
+	ProviderSioo   = "sioo"   // 希奥短信
+)
+
+// 手机号格式错误，更换服务商也无法发送，不做故障转移
+var ErrInvalidPhone = errors.New("手机号格式错误")
+
+// 短信内容
+//
+// 模板短信服务商使用 TemplateCode 和 Params，内容短信服务商使用 Content，
+// 调用方同时填写两者，才能在不同类型的服务商之间故障转移
+type Message struct {
+	Phone        string            // 手机号
+	TemplateCode string            // 模板编号，为空时使用服务商配置的默认模板
+	Params       map[string]string // 模板参数
+	Content      string            // 短信内容
+}
+
+// 发送错误
+type Error struct {
+	Provider string // 服务商
+	Code     string // 服务商返回的错误码
+	Message  string // 错误信息
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Provider + "短信发送错误：" + e.Message
+	}
+	return e.Provider + "短信发送错误：" + e.Code + "，" + e.Message
+}
+
+// 短信发送器
+type Sender interface {
+	Name() string                                // 服务商名称
+	Send(ctx context.Context, msg Message) error // 发送短信
+}
+
+// 短信发送器构造函数
+type SenderFactory func() (Sender, error)
+
+var (
+	senderMutex     sync.Mutex
+	senderFactories = map[string]SenderFactory{}
+	senders         = map[string]Sender{}
+)
+
+func init() {
+	RegisterSender(ProviderMock, func() (Sender, error) {
+		return NewMockSender(), nil
+	})
+	RegisterSender(ProviderAliyun, func() (Sender, error) {
+		return NewAliyunSender(), nil
+	})
+	RegisterSender(ProviderSioo, func() (Sender, error) {
+		return NewSiooSender(), nil
+	})
+}
+
+// 注册短信发送器，同名服务商会覆盖已注册的发送器
+func RegisterSender(name string, factory SenderFactory) {
+	senderMutex.Lock()
+	defer senderMutex.Unlock()
+
+	senderFactories[name] = factory
+	delete(senders, name)
+}
+
+// 获取短信发送器，发送器在首次使用时初始化
+func GetSender(name string) (Sender, error) {
+	senderMutex.Lock()
+	defer senderMutex.Unlock()
+
+	if sender, ok := senders[name]; ok {
+		return sender, nil
+	}
+	factory, ok := senderFactories[name]
+	if !ok {
+		return nil, errors.New("不支持的短信服务商：" + name)
+	}
+	sender, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	senders[name] = sender
+	return sender, nil
+}
+
+// 按名称获取多个发送器，返回按顺序故障转移的发送器
+func GetFailoverSender(names ...string) (Sender, error) {
+	list := []Sender{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		sender, err := GetSender(name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, sender)
+	}
+	if len(list) == 0 {
+		return nil, errors.New("未配置短信服务商")
+	}
+	if len(list) == 1 {
+		return list[0], nil
+	}
+	return NewFailoverSender(list...), nil
+}
+
+// 校验手机号
+func CheckPhone(phone string) error {
+	if !regexp.MustCompile(`^1[3-9]\d{9}$`).MatchString(phone) {
+		return ErrInvalidPhone
+	}
+	return nil
+}
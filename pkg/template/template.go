@@ -3,6 +3,7 @@ package template
 import (
 	"html/template"
 	"io"
+	"path"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,8 +14,9 @@ type Template struct {
 }
 
 func New(templatePath string) *Template {
+	// 模板方法需在解析前注册，渲染时不再修改模板集合
 	return &Template{
-		templates: template.Must(template.ParseGlob(templatePath)),
+		templates: template.Must(template.New("").Funcs(template.FuncMap{"html": html}).ParseGlob(templatePath)),
 	}
 }
 
@@ -23,6 +25,29 @@ func html(x string) interface{} {
 	return template.HTML(x)
 }
 
+// 模板是否存在
+func (t *Template) Exists(name string) bool {
+	return t.templates.Lookup(name) != nil
+}
+
+// 依次返回第一个存在的模板，未填写扩展名时默认使用 .html，都不存在时返回最后一个
+func (t *Template) Pick(names ...string) string {
+	name := ""
+	for _, v := range names {
+		if v == "" {
+			continue
+		}
+		if path.Ext(v) == "" {
+			v = v + ".html"
+		}
+		name = v
+		if t.Exists(name) {
+			return name
+		}
+	}
+	return name
+}
+
 // 模板渲染方法
 func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
 
@@ -31,8 +56,5 @@ func (t *Template) Render(w io.Writer, name string, data interface{}, c echo.Con
 		viewContext["reverse"] = c.Echo().Reverse
 	}
 
-	// 注册模板方法
-	t.templates = t.templates.Funcs(template.FuncMap{"html": html})
-
 	return t.templates.ExecuteTemplate(w, name, data)
 }
@@ -0,0 +1,311 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 命名正则和自定义规则
+var (
+	mutex   sync.RWMutex
+	regexes = map[string]*regexp.Regexp{
+		"phone":    regexp.MustCompile(`^1[3-9]\d{9}$`),
+		"username": regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{3,19}$`),
+		"numeric":  regexp.MustCompile(`^\d+$`),
+	}
+	rules = map[string]customRule{}
+)
+
+// 自定义规则，返回 false 表示校验不通过
+type Rule func(value reflect.Value, param string) bool
+
+// 自定义规则及错误提示
+type customRule struct {
+	handle  Rule
+	message string
+}
+
+// 字段错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段路径，如 order_details[0].pay_num
+	Rule    string `json:"rule"`    // 未通过的规则
+	Message string `json:"message"` // 错误提示
+}
+
+// 校验错误，包含全部未通过的字段
+type Errors []FieldError
+
+// 返回第一个字段的错误提示
+func (e Errors) Error() string {
+	if len(e) == 0 {
+		return ""
+	}
+	return e[0].Message
+}
+
+// 注册命名正则，供 regex 规则使用
+func RegisterRegex(name string, pattern string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	regexes[name] = regexp.MustCompile(pattern)
+}
+
+// 注册自定义规则，错误提示中的 {label}、{param} 会替换为字段名称和规则参数
+func RegisterRule(name string, rule Rule, message string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	rules[name] = customRule{handle: rule, message: message}
+}
+
+// 根据结构体标签校验参数，全部通过时返回 nil，否则返回 Errors
+//
+// 校验规则写在 validate 标签中，多个规则以逗号分隔，字段名称写在 label 标签中：
+//
+//	type RegisterReq struct {
+//		Username string `json:"username" validate:"required,regex=username" label:"用户名"`
+//		Phone    string `json:"phone" validate:"required,regex=phone" label:"手机号"`
+//	}
+//
+// 内置规则：
+//   - required：不能为零值，切片、数组、map 不能为空
+//   - min、max：字符串为字符数，数字为数值，切片、数组、map 为元素个数
+//   - len：字符串字符数或切片元素个数必须相等
+//   - oneof：取值必须为参数之一，参数以空格分隔，如 oneof=mp oa
+//   - regex：匹配通过 RegisterRegex 注册的正则，如 regex=phone
+//
+// 未设置 required 的字段为零值时跳过其他规则；结构体、结构体切片字段会递归校验
+func Validate(value interface{}) error {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := Errors{}
+	validateStruct(v, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// 校验结构体字段
+func validateStruct(v reflect.Value, prefix string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+
+		// 嵌入的结构体，如分页参数
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+			if value = indirect(value); value.IsValid() {
+				validateStruct(value, prefix, errs)
+			}
+			continue
+		}
+
+		path := prefix + fieldName(field)
+		label := field.Tag.Get("label")
+		if label == "" {
+			label = fieldName(field)
+		}
+
+		if err, ok := validateField(value, field.Tag.Get("validate"), label); !ok {
+			*errs = append(*errs, FieldError{Field: path, Rule: err.Rule, Message: err.Message})
+			continue
+		}
+		validateNested(value, path, errs)
+	}
+}
+
+// 递归校验结构体和结构体切片
+func validateNested(value reflect.Value, path string, errs *Errors) {
+	value = indirect(value)
+	if !value.IsValid() {
+		return
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if _, ok := value.Interface().(time.Time); ok {
+			return
+		}
+		validateStruct(value, path+".", errs)
+	case reflect.Slice, reflect.Array:
+		if indirectType(value.Type().Elem()).Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			validateNested(value.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+// 按顺序执行字段规则，遇到第一个未通过的规则时返回
+func validateField(value reflect.Value, tag string, label string) (FieldError, bool) {
+	if tag == "" || tag == "-" {
+		return FieldError{}, true
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		if strings.TrimSpace(rule) == "required" {
+			required = true
+		}
+	}
+	value = indirect(value)
+	if !value.IsValid() || isEmpty(value) {
+		if required {
+			return FieldError{Rule: "required", Message: label + "不能为空"}, false
+		}
+		return FieldError{}, true
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "" || name == "required" {
+			continue
+		}
+		if message, ok := check(value, name, param, label); !ok {
+			return FieldError{Rule: name, Message: message}, false
+		}
+	}
+	return FieldError{}, true
+}
+
+// 执行单个规则，返回错误提示和是否通过
+func check(value reflect.Value, name string, param string, label string) (string, bool) {
+	switch name {
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			panic("validator: invalid " + name + " param " + param)
+		}
+		return checkSize(value, name, limit, label)
+	case "oneof":
+		actual := fmt.Sprint(value.Interface())
+		for _, option := range strings.Fields(param) {
+			if option == actual {
+				return "", true
+			}
+		}
+		return label + "取值不正确", false
+	case "regex":
+		mutex.RLock()
+		re, ok := regexes[param]
+		mutex.RUnlock()
+		if !ok {
+			panic("validator: unknown regex " + param)
+		}
+		if value.Kind() != reflect.String || !re.MatchString(value.String()) {
+			return label + "格式不正确", false
+		}
+		return "", true
+	}
+
+	mutex.RLock()
+	custom, ok := rules[name]
+	mutex.RUnlock()
+	if !ok {
+		panic("validator: unknown rule " + name)
+	}
+	if !custom.handle(value, param) {
+		return strings.NewReplacer("{label}", label, "{param}", param).Replace(custom.message), false
+	}
+	return "", true
+}
+
+// 校验长度或数值范围
+func checkSize(value reflect.Value, name string, limit float64, label string) (string, bool) {
+	var (
+		size float64
+		unit string
+	)
+	switch value.Kind() {
+	case reflect.String:
+		size, unit = float64(utf8.RuneCountInString(value.String())), "个字符"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		size, unit = float64(value.Len()), "项"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		return "", true
+	}
+
+	param := strconv.FormatFloat(limit, 'f', -1, 64)
+	switch {
+	case name == "min" && size < limit:
+		if unit == "" {
+			return label + "不能小于" + param, false
+		}
+		return label + "不能少于" + param + unit, false
+	case name == "max" && size > limit:
+		if unit == "" {
+			return label + "不能大于" + param, false
+		}
+		return label + "不能超过" + param + unit, false
+	case name == "len" && size != limit:
+		return label + "必须为" + param + unit, false
+	}
+	return "", true
+}
+
+// 是否为零值，切片、map 长度为0时视为空
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	}
+	return value.IsZero()
+}
+
+// 字段名称，依次使用 json、query、form 标签
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// 取指针指向的值，空指针返回无效值
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// 取指针指向的类型
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -27,7 +27,7 @@ func NewWechatMiniProgram() *WechatMiniProgram {
 	}
 }
 
-// 获取微信授权用户信息
+// 获取微信授权用户信息，未传递加密数据时只返回 openid 和 unionid
 func (p *WechatMiniProgram) GetWechatUser(iv, code, encryptedData string) (encryptor.PlainData, error) {
 	// 登录凭证校验
 	authResponse, err := p.mini.GetAuth().Code2Session(code)
@@ -37,6 +37,13 @@ func (p *WechatMiniProgram) GetWechatUser(iv, code, encryptedData string) (encry
 	if authResponse.ErrCode != 0 {
 		return encryptor.PlainData{}, errors.New(authResponse.ErrMsg)
 	}
+	if encryptedData == "" {
+		return encryptor.PlainData{
+			OpenID:  authResponse.OpenID,
+			UnionID: authResponse.UnionID,
+		}, nil
+	}
+
 	// 解密数据，获取微信用户信息
 	wechatUser, err := p.mini.GetEncryptor().Decrypt(authResponse.SessionKey, encryptedData, iv)
 	if err != nil {
@@ -49,3 +56,15 @@ func (p *WechatMiniProgram) GetWechatUser(iv, code, encryptedData string) (encry
 
 	return *wechatUser, nil
 }
+
+// 通过手机号快速验证组件返回的 code 获取用户手机号，返回不带区号的手机号
+func (p *WechatMiniProgram) GetPhoneNumber(code string) (string, error) {
+	response, err := p.mini.GetAuth().GetPhoneNumber(code)
+	if err != nil {
+		return "", err
+	}
+	if response.PhoneInfo.PurePhoneNumber == "" {
+		return "", errors.New("获取手机号失败")
+	}
+	return response.PhoneInfo.PurePhoneNumber, nil
+}
@@ -1,8 +1,6 @@
 package wechat
 
 import (
-	"log"
-
 	"github.com/silenceper/wechat/v2/officialaccount"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
 )
@@ -26,14 +24,13 @@ func NewWechatTemplateMessage() *WechatTemplateMessage {
 	}
 }
 
-// 发送
-func (p *WechatTemplateMessage) Send(msg *Message) {
-	if _, err := p.officialaccount.GetTemplate().Send(&message.TemplateMessage{
+// 发送，请求处理中请通过队列异步发送
+func (p *WechatTemplateMessage) Send(msg *Message) error {
+	_, err := p.officialaccount.GetTemplate().Send(&message.TemplateMessage{
 		ToUser:     msg.ToUser,
 		TemplateID: msg.TemplateID,
 		Data:       msg.Data,
 		// URL:        utils.GetDomain() + "/pages/student/registration/detail/detail?id=", // 点击模板消息跳转的页面
-	}); err != nil {
-		log.Println("发送模板消息失败，错误信息：", err)
-	}
+	})
+	return err
 }